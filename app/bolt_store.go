@@ -0,0 +1,203 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"go.etcd.io/bbolt"
+	"strconv"
+	"time"
+)
+
+const (
+	BOLT_BUCKET       = "whiteboardbot"
+	BOLT_OPEN_TIMEOUT = 5 * time.Second
+)
+
+// BoltStore keeps everything in a BoltDB file, for running the bot on a single machine without Redis. Values are kept
+// under the same keys as in Redis, with their expiry alongside them.
+type BoltStore struct {
+	jsonStore
+	DB        *bbolt.DB
+	Clock     Clock
+	nextSweep time.Time
+}
+
+// NewBoltStore opens the file, creating it at the current schema version when it's new. Only one process can have it
+// open at a time, so opening waits up to BOLT_OPEN_TIMEOUT for another one to let go.
+func NewBoltStore(path string, clock Clock) (store *BoltStore, err error) {
+	db, err := bbolt.Open(path, 0600, &bbolt.Options{Timeout: BOLT_OPEN_TIMEOUT})
+	if err != nil {
+		return nil, fmt.Errorf("couldn't open %v: %v", path, err)
+	}
+	err = db.Update(func(tx *bbolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(BOLT_BUCKET))
+		if err != nil || bucket.Get([]byte(SCHEMA_VERSION_KEY)) != nil {
+			return err
+		}
+		return putValue(bucket, SCHEMA_VERSION_KEY, storedValue{Value: strconv.Itoa(SCHEMA_VERSION)})
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("couldn't set up %v: %v", path, err)
+	}
+	store = &BoltStore{DB: db, Clock: clock}
+	store.jsonStore = jsonStore{store}
+	return
+}
+
+func (store *BoltStore) Close() error {
+	return store.DB.Close()
+}
+
+func (store *BoltStore) Get(key string) (value string, ok bool) {
+	err := store.DB.View(func(tx *bbolt.Tx) error {
+		stored, found, err := getValue(tx.Bucket([]byte(BOLT_BUCKET)), []byte(key))
+		if found && !stored.expired(store.Clock.Now()) {
+			value, ok = stored.Value, true
+		}
+		return err
+	})
+	if err != nil {
+		fmt.Printf("Error occurred reading from BoltDB: %v", err)
+		return "", false
+	}
+	return
+}
+
+func (store *BoltStore) Set(key string, value string) {
+	store.SetExpiring(key, value, 0)
+}
+
+func (store *BoltStore) SetExpiring(key string, value string, expiry time.Duration) {
+	err := store.DB.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(BOLT_BUCKET))
+		if err := store.sweepExpired(bucket); err != nil {
+			return err
+		}
+		return putValue(bucket, key, expiringValue(store.Clock, value, expiry))
+	})
+	if err != nil {
+		fmt.Printf("Error occurred writing to BoltDB: %v", err)
+	}
+}
+
+func (store *BoltStore) Delete(key string) {
+	err := store.DB.Update(func(tx *bbolt.Tx) error {
+		return tx.Bucket([]byte(BOLT_BUCKET)).Delete([]byte(key))
+	})
+	if err != nil {
+		fmt.Printf("Error occurred deleting from BoltDB: %v", err)
+	}
+}
+
+// SetIfMissing sets the key unless it's already there, inside an update so no other one can set it in between.
+func (store *BoltStore) SetIfMissing(key string, value string, expiry time.Duration) (ok bool) {
+	err := store.DB.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(BOLT_BUCKET))
+		stored, found, err := getValue(bucket, []byte(key))
+		if err != nil || (found && !stored.expired(store.Clock.Now())) {
+			return err
+		}
+		ok = true
+		return putValue(bucket, key, expiringValue(store.Clock, value, expiry))
+	})
+	if err != nil {
+		fmt.Printf("Error occurred writing to BoltDB: %v", err)
+		return false
+	}
+	return
+}
+
+// Take gets the key and deletes it inside one update, so only one caller gets it.
+func (store *BoltStore) Take(key string) (value string, ok bool) {
+	err := store.DB.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(BOLT_BUCKET))
+		stored, found, err := getValue(bucket, []byte(key))
+		if found && !stored.expired(store.Clock.Now()) {
+			value, ok = stored.Value, true
+		}
+		if err != nil || !found {
+			return err
+		}
+		return bucket.Delete([]byte(key))
+	})
+	if err != nil {
+		fmt.Printf("Error occurred deleting from BoltDB: %v", err)
+		return "", false
+	}
+	return
+}
+
+// Keys lists the keys starting with the prefix, which BoltDB keeps in order.
+func (store *BoltStore) Keys(prefix string) (keys []string) {
+	err := store.DB.View(func(tx *bbolt.Tx) error {
+		now := store.Clock.Now()
+		cursor := tx.Bucket([]byte(BOLT_BUCKET)).Cursor()
+		for key, value := cursor.Seek([]byte(prefix)); key != nil && bytes.HasPrefix(key, []byte(prefix)); key, value = cursor.Next() {
+			var stored storedValue
+			if err := json.Unmarshal(value, &stored); err != nil {
+				return err
+			}
+			if !stored.expired(now) {
+				keys = append(keys, string(key))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Printf("Error occurred reading from BoltDB: %v", err)
+	}
+	return
+}
+
+func (store *BoltStore) SchemaVersion() int {
+	value, ok := store.Get(SCHEMA_VERSION_KEY)
+	version, err := strconv.Atoi(value)
+	if !ok || err != nil {
+		return 1
+	}
+	return version
+}
+
+// sweepExpired deletes the expired values, at most once every EXPIRY_SWEEP_INTERVAL. It's only called from inside an
+// update, and BoltDB runs those one at a time.
+func (store *BoltStore) sweepExpired(bucket *bbolt.Bucket) error {
+	now := store.Clock.Now()
+	if now.Before(store.nextSweep) {
+		return nil
+	}
+	var expired [][]byte
+	bucket.ForEach(func(key []byte, value []byte) error {
+		var stored storedValue
+		if json.Unmarshal(value, &stored) == nil && stored.expired(now) {
+			expired = append(expired, append([]byte{}, key...))
+		}
+		return nil
+	})
+	for _, key := range expired {
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+	store.nextSweep = now.Add(EXPIRY_SWEEP_INTERVAL)
+	return nil
+}
+
+func getValue(bucket *bbolt.Bucket, key []byte) (stored storedValue, found bool, err error) {
+	value := bucket.Get(key)
+	if value == nil {
+		return
+	}
+	err = json.Unmarshal(value, &stored)
+	return stored, err == nil, err
+}
+
+func putValue(bucket *bbolt.Bucket, key string, stored storedValue) error {
+	value, err := json.Marshal(stored)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(key), value)
+}
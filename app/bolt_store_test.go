@@ -0,0 +1,86 @@
+package app_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+	"github.com/pivotal-sydney/whiteboardbot/spec"
+	"go.etcd.io/bbolt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var _ = Describe("BoltStore", func() {
+	var (
+		directory string
+		path      string
+		clock     *spec.FakeClock
+		store     *BoltStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		directory, err = ioutil.TempDir("", "whiteboardbot")
+		Expect(err).To(BeNil())
+		path = filepath.Join(directory, "whiteboardbot.db")
+		clock = &spec.FakeClock{CurrentTime: spec.MockClock{}.Now()}
+		store, err = NewBoltStore(path, clock)
+		Expect(err).To(BeNil())
+	})
+
+	AfterEach(func() {
+		store.Close()
+		os.RemoveAll(directory)
+	})
+
+	It("should keep everything in the file", func() {
+		store.SetStandup("C123", model.Standup{Id: 1, Title: "Sydney"})
+		store.Close()
+
+		var err error
+		store, err = NewBoltStore(path, clock)
+		Expect(err).To(BeNil())
+		standup, ok := store.GetStandup("C123")
+		Expect(ok).To(BeTrue())
+		Expect(standup.Title).To(Equal("Sydney"))
+		Expect(store.SchemaVersion()).To(Equal(SCHEMA_VERSION))
+	})
+
+	It("should sweep expired values out of the file", func() {
+		store.SetEntry("aleung", model.Interesting{Entry: &model.Entry{Title: "Something interesting", ItemKind: "Interesting"}})
+		clock.Advance(ENTRY_EXPIRY + EXPIRY_SWEEP_INTERVAL)
+		store.Set(PostKey("C123"), "1")
+
+		store.DB.View(func(tx *bbolt.Tx) error {
+			Expect(tx.Bucket([]byte(BOLT_BUCKET)).Get([]byte(EntryKey("aleung")))).To(BeNil())
+			return nil
+		})
+	})
+
+	It("should migrate a file from schema v2", func() {
+		store.Set(SCHEMA_VERSION_KEY, "2")
+		store.Set("wb:v2:channel:C123:standups", `[{"id":3,"title":"Singapore","alias":"sg"}]`)
+		store.Set("wb:v2:channel:C123:schedule:sg", `{"days":[5],"hour":10}`)
+		store.Set("wb:v2:schedules", "C123")
+		store.SetExpiring("wb:v2:user:aleung:entry", model.MarshalEntry(model.Interesting{Entry: &model.Entry{Title: "Something interesting", ItemKind: "Interesting"}}), time.Hour)
+
+		migrated, err := store.Migrate()
+		Expect(err).To(BeNil())
+		Expect(migrated).To(Equal(3))
+		Expect(store.SchemaVersion()).To(Equal(SCHEMA_VERSION))
+		Expect(store.Keys("wb:v2:")).To(BeEmpty())
+		standups, _ := store.GetNamedStandups("C123")
+		Expect(standups).To(Equal([]model.Standup{{Id: 3, Title: "Singapore", Alias: "sg"}}))
+		_, ok := store.GetSchedule("C123", "sg")
+		Expect(ok).To(BeTrue())
+
+		_, ok = store.GetEntry("aleung")
+		Expect(ok).To(BeTrue())
+		clock.Advance(time.Hour)
+		_, ok = store.GetEntry("aleung")
+		Expect(ok).To(BeFalse())
+	})
+})
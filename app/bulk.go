@@ -0,0 +1,145 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/nlopes/slack"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"strings"
+	"time"
+)
+
+const (
+	BULK_KIND_SEPARATOR = ":"
+	BULK_BODY_SEPARATOR = "|"
+)
+
+var bulkKinds = []struct {
+	name   string
+	create func(clock Clock, author string, title string, standup Standup) (entryType interface{})
+}{
+	{FACES_SECTION, NewFace},
+	{HELPS_SECTION, NewHelp},
+	{INTERESTINGS_SECTION, NewInteresting},
+	{EVENTS_SECTION, NewEvent},
+}
+
+// handleBulkCommand creates an entry for each line like `i: Title | body`, and remembers them so `wb undo` can delete
+// them again.
+func (whiteboard WhiteboardApp) handleBulkCommand(input string, ev *slack.MessageEvent, context CommandContext) {
+	standup, slackUser, entryType, ok := whiteboard.getEntryDetails(ev, context)
+	if !ok {
+		return
+	}
+	var lines []string
+	for _, line := range strings.Split(input, "\n") {
+		if len(strings.TrimSpace(line)) > 0 {
+			lines = append(lines, strings.TrimSpace(line))
+		}
+	}
+	if len(lines) == 0 {
+		whiteboard.SlackClient.PostReply("Put one entry on each line after `wb bulk`, like this:\n```wb bulk\ni: Title | body\nh: Title\nf: Name```", ev.Channel, THUMBS_DOWN)
+		return
+	}
+
+	var buffer bytes.Buffer
+	var itemIds []string
+	for i, line := range lines {
+		entryType, err := whiteboard.parseBulkLine(standup, slackUser, line, context)
+		if err == nil {
+			var itemId string
+			if itemId, err = PostEntryToWhiteboard(whiteboard.restClientFor(standup), entryType); err == nil {
+				entryType.GetEntry().Id = itemId
+				itemIds = append(itemIds, itemId)
+				whiteboard.Store.Set(AuthorKey(standup.Backend, itemId), ev.User)
+			} else {
+				err = errors.New(RestErrorMessage(err))
+			}
+		}
+		if err != nil {
+			buffer.WriteString(fmt.Sprintf("\nLine %v: %v", i + 1, err))
+			continue
+		}
+		entry := entryType.GetEntry()
+		buffer.WriteString(fmt.Sprintf("\nLine %v: created %v *%v* %v", i + 1, strings.ToLower(entry.ItemKind), entry.Title, entry.GetDateString()))
+	}
+	buffer.WriteString(fmt.Sprintf("\n\nCreated %v of %v entries.", len(itemIds), len(lines)))
+
+	status := THUMBS_DOWN
+	if len(itemIds) > 0 {
+		status = THUMBS_UP
+		whiteboard.Store.SetBulkEntries(ev.Channel, slackUser.Username, BulkEntries{ItemIds: itemIds, Standup: standup, Entry: entrySnapshot(entryType)})
+		buffer.WriteString(" Changed your mind? `wb undo` deletes them again.")
+	}
+	whiteboard.SlackClient.PostMessage(strings.TrimPrefix(buffer.String(), "\n"), ev.Channel, status)
+}
+
+func (whiteboard WhiteboardApp) parseBulkLine(standup Standup, slackUser SlackUser, line string, context CommandContext) (entryType EntryType, err error) {
+	parts := strings.SplitN(line, BULK_KIND_SEPARATOR, 2)
+	kind := strings.ToLower(strings.TrimSpace(parts[0]))
+	var create func(clock Clock, author string, title string, standup Standup) (entryType interface{})
+	for _, bulkKind := range bulkKinds {
+		if matches(kind, bulkKind.name) {
+			create = bulkKind.create
+		}
+	}
+	if len(parts) < 2 || create == nil {
+		err = errors.New("Start the line with the kind of entry, like `i: Title`, `h: Title`, `e: Title` or `f: Name`")
+		return
+	}
+
+	entryInput, err := ParseEntryInput(strings.Replace(parts[1], BULK_BODY_SEPARATOR, "\n", 1))
+	if err != nil {
+		return
+	}
+	if len(entryInput.Title) == 0 {
+		err = errors.New("The title/name can't be empty!")
+		return
+	}
+
+	entryType = create(whiteboard.Clock, slackUser.Author, entryInput.Title, standup).(EntryType)
+	if _, face := entryType.(Face); face && len(entryInput.Body) > 0 {
+		err = errors.New("Face does not have a body!")
+		return
+	}
+	entry := entryType.GetEntry()
+	entry.Body = entryInput.Body
+	entry.Public = context.Public || entryInput.Public
+	if len(entryInput.Date) > 0 {
+		var date time.Time
+		if date, err = ParseDate(whiteboard.Clock, entryInput.Date, standup.Location()); err != nil {
+			return
+		}
+		entry.Date = date.Format(DATE_FORMAT)
+	}
+	return
+}
+
+// rollBackBulk deletes the entries created by the user's last `wb bulk` in the channel, from the standup they went to.
+// Any the whiteboard wouldn't delete are kept for the next `wb undo`.
+func (whiteboard WhiteboardApp) rollBackBulk(bulk BulkEntries, slackUser SlackUser, entryType EntryType, channel string) {
+	var remaining []string
+	for _, itemId := range bulk.ItemIds {
+		if !whiteboard.deleteItem(bulk.Standup, slackUser, entryType, itemId, channel) {
+			remaining = append(remaining, itemId)
+		}
+	}
+	deleted := len(bulk.ItemIds) - len(remaining)
+	if len(remaining) > 0 {
+		bulk.ItemIds = remaining
+		whiteboard.Store.SetBulkEntries(channel, slackUser.Username, bulk)
+		whiteboard.SlackClient.PostMessage(fmt.Sprintf("Undid your bulk entries, %v of %v have been deleted from the whiteboard. `wb undo` tries the rest again.", deleted, deleted + len(remaining)), channel, THUMBS_DOWN)
+		return
+	}
+	whiteboard.Store.Delete(BulkKey(channel, slackUser.Username))
+	whiteboard.SlackClient.PostMessage(fmt.Sprintf("Undid your bulk entries, %v of %v have been deleted from the whiteboard.", deleted, deleted), channel, THUMBS_UP)
+}
+
+// entrySnapshot is how the user's current entry looks, so `wb undo` can tell whether it's changed since their bulk.
+func entrySnapshot(entryType EntryType) string {
+	if missingEntry(entryType) {
+		return ""
+	}
+	return MarshalEntry(entryType)
+}
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"github.com/nlopes/slack"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"sort"
+)
+
+// CommandContext is what a message picked for its command besides the input: the standup named with `wb @name`,
+// whether a new entry is public with a `!` after the command, and for a reply in a card's thread, the card's entry to
+// update instead of the user's current one. It's given to each command rather than kept on the WhiteboardApp, which is
+// shared by every message.
+type CommandContext struct {
+	StandupName string
+	Public      bool
+	Entry       EntryType
+}
+
+type Command struct {
+	Name    string
+	Aliases []string
+	Handler func(whiteboard WhiteboardApp, input string, ev *slack.MessageEvent, context CommandContext)
+}
+
+type CommandRegistry struct {
+	commands []Command
+}
+
+func NewCommandRegistry() *CommandRegistry {
+	return &CommandRegistry{}
+}
+
+func (registry *CommandRegistry) Register(name string, handler func(whiteboard WhiteboardApp, input string, ev *slack.MessageEvent, context CommandContext), aliases ...string) {
+	registry.commands = append(registry.commands, Command{Name: name, Aliases: aliases, Handler: handler})
+}
+
+// Resolve finds the command for a keyword: an exact name or alias wins, otherwise the keyword has to be
+// a prefix of exactly one command name. Ambiguous prefixes return the names of all matching commands.
+func (registry *CommandRegistry) Resolve(keyword string) (command Command, candidates []string, ok bool) {
+	for _, command = range registry.commands {
+		if keyword == command.Name {
+			return command, nil, true
+		}
+		for _, alias := range command.Aliases {
+			if keyword == alias {
+				return command, nil, true
+			}
+		}
+	}
+
+	var matched []Command
+	for _, command := range registry.commands {
+		if matches(keyword, command.Name) {
+			matched = append(matched, command)
+			candidates = append(candidates, command.Name)
+		}
+	}
+	sort.Strings(candidates)
+
+	if len(matched) == 1 {
+		return matched[0], nil, true
+	}
+	return Command{}, candidates, false
+}
@@ -0,0 +1,86 @@
+package app_test
+
+import (
+	"github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/spec"
+)
+
+var _ = Describe("Command Registry", func() {
+
+	var registry *CommandRegistry
+
+	BeforeEach(func() {
+		noop := func(whiteboard WhiteboardApp, input string, ev *slack.MessageEvent, context CommandContext) {}
+		registry = NewCommandRegistry()
+		registry.Register("present", noop, "p")
+		registry.Register("public", noop)
+		registry.Register("private", noop)
+		registry.Register("body", noop, "b")
+	})
+
+	Context("resolving a keyword", func() {
+		It("should prefer an alias over a prefix match", func() {
+			command, _, ok := registry.Resolve("p")
+			Expect(ok).To(BeTrue())
+			Expect(command.Name).To(Equal("present"))
+		})
+
+		It("should resolve a unique prefix", func() {
+			command, _, ok := registry.Resolve("pub")
+			Expect(ok).To(BeTrue())
+			Expect(command.Name).To(Equal("public"))
+		})
+
+		It("should resolve the full command name", func() {
+			command, _, ok := registry.Resolve("private")
+			Expect(ok).To(BeTrue())
+			Expect(command.Name).To(Equal("private"))
+		})
+
+		It("should report all candidates for an ambiguous prefix", func() {
+			_, candidates, ok := registry.Resolve("pr")
+			Expect(ok).To(BeFalse())
+			Expect(candidates).To(Equal([]string{"present", "private"}))
+		})
+
+		It("should not resolve an unknown keyword", func() {
+			_, candidates, ok := registry.Resolve("bodyguard")
+			Expect(ok).To(BeFalse())
+			Expect(candidates).To(BeEmpty())
+		})
+	})
+
+	Context("with the whiteboard commands", func() {
+		var whiteboard WhiteboardApp
+
+		BeforeEach(func() {
+			whiteboard = NewWhiteboard(&spec.MockSlackClient{}, &spec.MockRestClient{}, spec.MockClock{}, &spec.MockStore{})
+		})
+
+		aliases := []struct{ alias, command string }{
+			{"r", "register"},
+			{"p", "present"},
+			{"f", "faces"},
+			{"i", "interestings"},
+			{"h", "helps"},
+			{"e", "events"},
+			{"t", "title"},
+			{"n", "name"},
+			{"b", "body"},
+			{"d", "date"},
+		}
+
+		for _, alias := range aliases {
+			alias := alias
+			It("should resolve `" + alias.alias + "` to " + alias.command, func() {
+				Expect(USAGE).To(ContainSubstring("`" + alias.command + "`, `" + alias.alias + "`"))
+				command, _, ok := whiteboard.Commands.Resolve(alias.alias)
+				Expect(ok).To(BeTrue())
+				Expect(command.Name).To(Equal(alias.command))
+			})
+		}
+	})
+})
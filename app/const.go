@@ -3,26 +3,61 @@ package app
 const(
 	THUMBS_UP = ":+1:\n"
 	THUMBS_DOWN = ":-1:\n"
+	DELETE_CONFIRMATION = "yes"
+	EMAIL_CONFIRMATION = "yes"
+	UNREGISTER_CONFIRMATION = "yes"
+	PUBLIC_SUFFIX = "!"
+	STANDUP_SELECTOR = "@"
+	STANDUP_ALIAS_KEYWORD = "as"
+	SECTION_ORDER_COMMAND = "order"
 	USAGE =
 	"*Usage*:\n" +
 	"        `wb [command] [text...]`\n" +
 	"    where commands include:\n" +
 	"*Registration Command*\n" +
-	"        `register`, `r` - followed by <standup_id>, registers current channel to Whiteboard's standup id\n" +
+	"        `register`, `r` - followed by <standup_id>, registers current channel to Whiteboard's standup id. Put a whiteboard name first (i.e. `wb r singapore 3`) to use a whiteboard other than the default\n" +
+	"        Add `as <name>` (i.e. `wb r 12 as sydney`) to register another standup in the channel, and pick it for a command with `wb @sydney i My title`\n" +
+	"        `standups` - lists the standups registered in the channel\n" +
+	"        `status` - shows the title, id, time zone and whiteboard of the channel's standup\n" +
+	"        `unregister` - removes the channel's standups, schedule and settings. Pick a standup (i.e. `wb @sydney unregister`) to only remove that one. Confirm with `wb unregister yes`\n" +
 	"\n" +
 	"*Presentation Command*\n" +
 	"		 `present`, `p` - presents today's standup. Follow with number of days to limit the entries shown by date (i.e. `wb p 2` will only return entries for the next 2 days)\n" +
+	"        Follow with sections to only present those (i.e. `wb p helps 2` will only return helps for the next 2 days)\n" +
+	"        Follow with dates to only present entries for those (i.e. `wb p this week` or `wb p 2015-12-01..2015-12-05`)\n" +
+	"        `present order` - followed by sections (i.e. `wb p order helps faces`), changes the order this channel's standup is presented in\n" +
+	"        `schedule` - followed by days, a time and an optional reminder (i.e. `wb schedule weekdays 09:05 remind 10`), presents the standup automatically. `wb schedule off` stops it\n" +
 	"\n" +
 	"*Create Commands*\n" +
 	"        `faces`, `f` - followed by a title, creates a new faces entry\n" +
 	"        `interestings`, `i` - followed by a title, creates a new interestings entry\n" +
 	"        `helps`, `h` - followed by a title, creates a new helps entry\n" +
 	"        `events`, `e` - followed by a title, creates a new events entry\n" +
+	"        `new` - opens a form to fill in a whole entry at once (only from the `/wb` slash command)\n" +
+	"        `bulk` - followed by one entry per line (i.e. `i: Title | body`, `h: Title` or `f: Name`), creates them all at once. `wb undo` straight after deletes them again\n" +
+	"        Add a `!` to a command followed by a title (i.e. `wb i! My title`) to show the new entry on the public whiteboard\n" +
+	"        Lines after the title become the body, and `--date <date>` or `--public` after a title sets those details too (i.e. `wb e Meetup --date next friday`)\n" +
 	"\n" +
 	"*Detail Commands* (updates details of a started entry)\n" +
 	"        `title`, `t`, `name`, `n` - updates a name/title detail to a started entry\n" +
 	"        `body`, `b` - updates a body detail to a started entry\n" +
-	"        `date`, `d` - updates a date detail to a started entry (YYYY-MM-DD)\n" +
+	"        `date`, `d` - updates a date detail to a started entry (YYYY-MM-DD, `3 Dec`, `tomorrow`, `next friday` or `+3`)\n" +
+	"        `public`, `private` - shows a started entry on the public whiteboard, or hides it again\n" +
+	"\n" +
+	"*Edit Command*\n" +
+	"        `edit` - followed by an <item_id> or some text from the title or body, starts editing an existing entry again\n" +
+	"\n" +
+	"*Delete Command*\n" +
+	"        `delete` - deletes a started entry, or the entry with the <item_id> that follows. Confirm with `wb delete yes`\n" +
+	"\n" +
+	"*History Commands*\n" +
+	"        `undo` - takes back the last change to a started entry, or the entries from your last `wb bulk` if that's the last thing you did\n" +
+	"        `redo` - makes an undone change to a started entry again\n" +
+	"        `history` - lists the changes made to a started entry, who made them and when\n" +
+	"\n" +
+	"*Post Commands*\n" +
+	"        `archive` - archives today's entries into a new post, optionally followed by a title for it\n" +
+	"        `email` - previews the email for the archived post. Send it with `wb email yes`\n" +
 	"\n" +
 	"Example:\n" +
 	"        `wb f New Face!` - will create a new face with the name 'New Face!'\n" +
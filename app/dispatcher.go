@@ -0,0 +1,98 @@
+package app
+
+import (
+	"github.com/nlopes/slack"
+	"sync"
+)
+
+type Dispatcher struct {
+	// Key picks the queue a message waits in, the user's own unless it says otherwise.
+	Key     func(ev *slack.MessageEvent) string
+	handler func(ev *slack.MessageEvent)
+	mutex   sync.Mutex
+	pending map[string][]func()
+}
+
+func NewDispatcher(handler func(ev *slack.MessageEvent)) *Dispatcher {
+	return &Dispatcher{handler: handler, pending: make(map[string][]func())}
+}
+
+// Dispatch handles messages concurrently across users, but one at a time and in arrival order for each user. It's the
+// only thing keeping a user's commands apart, so everything a user does goes through it.
+func (dispatcher *Dispatcher) Dispatch(ev *slack.MessageEvent) {
+	key := ev.User
+	if dispatcher.Key != nil {
+		key = dispatcher.Key(ev)
+	}
+	dispatcher.DispatchTask(key, func() {
+		dispatcher.handler(ev)
+	})
+}
+
+// DispatchTask runs the task after every other task dispatched with the same key.
+func (dispatcher *Dispatcher) DispatchTask(key string, task func()) {
+	dispatcher.mutex.Lock()
+	defer dispatcher.mutex.Unlock()
+
+	queue, running := dispatcher.pending[key]
+	dispatcher.pending[key] = append(queue, task)
+	if !running {
+		go dispatcher.drain(key)
+	}
+}
+
+func (dispatcher *Dispatcher) drain(key string) {
+	for {
+		dispatcher.mutex.Lock()
+		queue := dispatcher.pending[key]
+		if len(queue) == 0 {
+			delete(dispatcher.pending, key)
+			dispatcher.mutex.Unlock()
+			return
+		}
+		task := queue[0]
+		dispatcher.pending[key] = queue[1:]
+		dispatcher.mutex.Unlock()
+
+		task()
+	}
+}
+
+// EntryLocks keeps updates to the same entry apart when they wait in different Dispatcher queues, like a reply in the
+// entry's thread and its owner's own `wb b`.
+type EntryLocks struct {
+	mutex sync.Mutex
+	locks map[string]*entryLock
+}
+
+type entryLock struct {
+	sync.Mutex
+	waiting int
+}
+
+func NewEntryLocks() *EntryLocks {
+	return &EntryLocks{locks: make(map[string]*entryLock)}
+}
+
+// Lock waits for everyone else updating the entry to be done, and returns the func that lets the next one go.
+func (locks *EntryLocks) Lock(itemId string) (unlock func()) {
+	locks.mutex.Lock()
+	lock, ok := locks.locks[itemId]
+	if !ok {
+		lock = &entryLock{}
+		locks.locks[itemId] = lock
+	}
+	lock.waiting++
+	locks.mutex.Unlock()
+
+	lock.Lock()
+	return func() {
+		lock.Unlock()
+		locks.mutex.Lock()
+		defer locks.mutex.Unlock()
+		lock.waiting--
+		if lock.waiting == 0 {
+			delete(locks.locks, itemId)
+		}
+	}
+}
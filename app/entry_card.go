@@ -0,0 +1,124 @@
+package app
+
+import (
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"strings"
+)
+
+const (
+	EDIT_BODY_ACTION    = "edit_body"
+	CHANGE_DATE_ACTION  = "change_date"
+	DELETE_ACTION       = "delete"
+	MAKE_PUBLIC_ACTION  = "make_public"
+	MAKE_PRIVATE_ACTION = "make_private"
+)
+
+type CardAttachment struct {
+	Color    string      `json:"color,omitempty"`
+	Fallback string      `json:"fallback"`
+	Blocks   []CardBlock `json:"blocks"`
+}
+
+type CardBlock struct {
+	Type     string        `json:"type"`
+	BlockId  string        `json:"block_id,omitempty"`
+	Text     *CardText     `json:"text,omitempty"`
+	Fields   []CardText    `json:"fields,omitempty"`
+	Elements []CardElement `json:"elements,omitempty"`
+	Label    *CardText     `json:"label,omitempty"`
+	Element  *CardElement  `json:"element,omitempty"`
+	Optional bool          `json:"optional,omitempty"`
+}
+
+type CardText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type CardElement struct {
+	Type         string       `json:"type"`
+	ActionId     string       `json:"action_id,omitempty"`
+	Text         *CardText    `json:"text,omitempty"`
+	Placeholder  *CardText    `json:"placeholder,omitempty"`
+	Value        string       `json:"value,omitempty"`
+	Style        string       `json:"style,omitempty"`
+	InitialDate  string       `json:"initial_date,omitempty"`
+	InitialValue string       `json:"initial_value,omitempty"`
+	Confirm      *CardConfirm `json:"confirm,omitempty"`
+	Options      []CardOption `json:"options,omitempty"`
+	Multiline    bool         `json:"multiline,omitempty"`
+}
+
+type CardOption struct {
+	Text  *CardText `json:"text"`
+	Value string    `json:"value"`
+}
+
+type CardConfirm struct {
+	Title   *CardText `json:"title"`
+	Text    CardText  `json:"text"`
+	Confirm *CardText `json:"confirm"`
+	Deny    *CardText `json:"deny"`
+}
+
+func NewEntryCard(entry *Entry) []CardAttachment {
+	fields := []CardText{
+		markdownText("*Title*\n" + entry.Title),
+		markdownText("*Author*\n" + entry.Author),
+		markdownText("*Date*\n" + entry.GetDateString()),
+		markdownText("*Visibility*\n" + entry.GetVisibility()),
+	}
+	if len(entry.Body) > 0 {
+		fields = append(fields, markdownText("*Body*\n"+entry.Body))
+	}
+
+	var elements []CardElement
+	if entry.ItemKind != "New face" {
+		elements = append(elements, button(EDIT_BODY_ACTION, "Edit body", entry.Id))
+	}
+	elements = append(elements, CardElement{Type: "datepicker", ActionId: CHANGE_DATE_ACTION, Placeholder: plainText("Change date"), InitialDate: entry.Date})
+	if entry.Public {
+		elements = append(elements, button(MAKE_PRIVATE_ACTION, "Make private", entry.Id))
+	} else {
+		elements = append(elements, button(MAKE_PUBLIC_ACTION, "Make public", entry.Id))
+	}
+	deleteButton := button(DELETE_ACTION, "Delete", entry.Id)
+	deleteButton.Style = "danger"
+	deleteButton.Confirm = &CardConfirm{Title: plainText("Delete entry?"), Text: markdownText("This removes *" + entry.Title + "* from the whiteboard."), Confirm: plainText("Delete"), Deny: plainText("Cancel")}
+	elements = append(elements, deleteButton)
+
+	blocks := []CardBlock{
+		CardBlock{Type: "section", Fields: fields},
+		CardBlock{Type: "actions", BlockId: NewEntryRef(entry).String(), Elements: elements},
+	}
+	return []CardAttachment{CardAttachment{Color: "#3AA3E3", Fallback: entry.String(), Blocks: blocks}}
+}
+
+// CardBody reads the body back from an entry's card, as Slack sends the card along when one of its buttons is pressed.
+func CardBody(card []CardAttachment) string {
+	for _, attachment := range card {
+		for _, block := range attachment.Blocks {
+			for _, field := range block.Fields {
+				if strings.HasPrefix(field.Text, "*Body*\n") {
+					return slackUnescaper.Replace(strings.TrimPrefix(field.Text, "*Body*\n"))
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// slackUnescaper undoes the escaping Slack does to the text of the messages it sends.
+var slackUnescaper = strings.NewReplacer("&lt;", "<", "&gt;", ">", "&amp;", "&")
+
+func button(actionId string, text string, itemId string) CardElement {
+	return CardElement{Type: "button", ActionId: actionId, Text: plainText(text), Value: itemId}
+}
+
+func markdownText(text string) CardText {
+	return CardText{Type: "mrkdwn", Text: text}
+}
+
+func plainText(text string) *CardText {
+	return &CardText{Type: "plain_text", Text: text}
+}
@@ -0,0 +1,42 @@
+package app_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+)
+
+var _ = Describe("Entry Card", func() {
+
+	It("should not offer to edit the body of a face", func() {
+		face := model.Entry{Id: "1", Title: "Dariusz", ItemKind: "New face"}
+		card := NewEntryCard(&face)
+		for _, element := range card[0].Blocks[1].Elements {
+			Expect(element.ActionId).NotTo(Equal(EDIT_BODY_ACTION))
+		}
+	})
+
+	It("should only offer to make a private entry public", func() {
+		entry := model.Entry{Id: "1", Title: "Something", Public: true}
+		card := NewEntryCard(&entry)
+		Expect(card[0].Blocks[1].BlockId).To(Equal(":1"))
+		for _, element := range card[0].Blocks[1].Elements {
+			Expect(element.ActionId).NotTo(Equal(MAKE_PUBLIC_ACTION))
+		}
+	})
+
+	It("should keep the whiteboard of the entry along with its id", func() {
+		entry := model.Entry{Id: "1", Title: "Something", Backend: "singapore"}
+		card := NewEntryCard(&entry)
+		Expect(ParseEntryRef(card[0].Blocks[1].BlockId)).To(Equal(EntryRef{Backend: "singapore", ItemId: "1"}))
+		Expect(ParseEntryRef("1")).To(Equal(EntryRef{ItemId: "1", AnyBackend: true}))
+	})
+
+	It("should read the body back from the card", func() {
+		entry := model.Entry{Id: "1", Title: "Something", Body: "more &amp; more"}
+		Expect(CardBody(NewEntryCard(&entry))).To(Equal("more & more"))
+		entry.Body = ""
+		Expect(CardBody(NewEntryCard(&entry))).To(Equal(""))
+	})
+})
@@ -0,0 +1,80 @@
+package app
+
+import (
+	"errors"
+	"strings"
+)
+
+const (
+	DATE_FLAG   = "--date"
+	PUBLIC_FLAG = "--public"
+)
+
+// EntryInput is what a create command says about the new entry: the first line is the title, the lines after it the
+// body, and flags like `--date 2015-12-01` or `--public` at the end of any line set the other details.
+type EntryInput struct {
+	Title  string
+	Body   string
+	Date   string
+	Public bool
+}
+
+func ParseEntryInput(input string) (entryInput EntryInput, err error) {
+	var lines []string
+	for _, line := range strings.Split(input, "\n") {
+		if line, err = entryInput.readFlags(line); err != nil {
+			return
+		}
+		if len(lines) > 0 || len(strings.TrimSpace(line)) > 0 {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > 0 {
+		entryInput.Title = strings.TrimSpace(lines[0])
+		entryInput.Body = strings.TrimSpace(strings.Join(lines[1:], "\n"))
+	}
+	return
+}
+
+// readFlags takes the flags out of a line. A flag's value runs until the next flag or the end of the line.
+func (entryInput *EntryInput) readFlags(line string) (rest string, err error) {
+	words := strings.Split(line, " ")
+	var kept []string
+	for i := 0; i < len(words); i++ {
+		switch normalizeFlag(words[i]) {
+		case PUBLIC_FLAG:
+			entryInput.Public = true
+		case DATE_FLAG:
+			end := i + 1
+			for end < len(words) && !isFlag(words[end]) {
+				end++
+			}
+			if entryInput.Date = strings.TrimSpace(strings.Join(words[i + 1:end], " ")); len(entryInput.Date) == 0 {
+				return "", errors.New("Put a date after --date, like `--date 2015-12-01` or `--date tomorrow`")
+			}
+			i = end - 1
+		default:
+			kept = append(kept, words[i])
+		}
+	}
+	if len(kept) == len(words) {
+		return line, nil
+	}
+	return strings.TrimRight(strings.Join(kept, " "), " \t"), nil
+}
+
+func isFlag(word string) bool {
+	flag := normalizeFlag(word)
+	return flag == DATE_FLAG || flag == PUBLIC_FLAG
+}
+
+// normalizeFlag undoes Slack turning `--` into a dash on phones.
+func normalizeFlag(word string) string {
+	word = strings.ToLower(strings.TrimSpace(word))
+	for _, dash := range []string{"—", "–"} {
+		if strings.HasPrefix(word, dash) {
+			return "--" + strings.TrimPrefix(word, dash)
+		}
+	}
+	return word
+}
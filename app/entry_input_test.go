@@ -0,0 +1,47 @@
+package app_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+)
+
+var _ = Describe("Entry Input", func() {
+
+	It("should take the whole of a single line as the title", func() {
+		entryInput, err := ParseEntryInput("Something interesting")
+		Expect(err).To(BeNil())
+		Expect(entryInput).To(Equal(EntryInput{Title: "Something interesting"}))
+	})
+
+	It("should take the lines after the first as the body", func() {
+		entryInput, _ := ParseEntryInput("Something interesting\nlonger body text\n\n  - with a list")
+		Expect(entryInput.Title).To(Equal("Something interesting"))
+		Expect(entryInput.Body).To(Equal("longer body text\n\n  - with a list"))
+	})
+
+	It("should read flags at the end of the title", func() {
+		entryInput, _ := ParseEntryInput("Meetup --date next friday --public\nAt the office")
+		Expect(entryInput).To(Equal(EntryInput{Title: "Meetup", Body: "At the office", Date: "next friday", Public: true}))
+	})
+
+	It("should read flags on a line of their own", func() {
+		entryInput, _ := ParseEntryInput("Meetup\nAt the office\n--date 2015-12-01")
+		Expect(entryInput).To(Equal(EntryInput{Title: "Meetup", Body: "At the office", Date: "2015-12-01"}))
+	})
+
+	It("should read flags that Slack turned into a dash", func() {
+		entryInput, _ := ParseEntryInput("Meetup —public —date tomorrow")
+		Expect(entryInput).To(Equal(EntryInput{Title: "Meetup", Date: "tomorrow", Public: true}))
+	})
+
+	It("should need a value for the date flag", func() {
+		_, err := ParseEntryInput("Meetup --date --public")
+		Expect(err.Error()).To(Equal("Put a date after --date, like `--date 2015-12-01` or `--date tomorrow`"))
+	})
+
+	It("should skip blank lines before the title", func() {
+		entryInput, _ := ParseEntryInput("\n  \nMeetup")
+		Expect(entryInput.Title).To(Equal("Meetup"))
+	})
+})
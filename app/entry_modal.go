@@ -0,0 +1,195 @@
+package app
+
+import (
+	"encoding/json"
+	"github.com/nlopes/slack"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"time"
+)
+
+const (
+	NEW_ENTRY_CALLBACK = "new_entry"
+	EDIT_BODY_CALLBACK = "edit_body"
+	ENTRY_KIND_BLOCK   = "kind"
+	ENTRY_TITLE_BLOCK  = "title"
+	ENTRY_BODY_BLOCK   = "body"
+	ENTRY_DATE_BLOCK   = "date"
+)
+
+type EntryModal struct {
+	Type            string      `json:"type"`
+	CallbackId      string      `json:"callback_id"`
+	Title           *CardText   `json:"title"`
+	Submit          *CardText   `json:"submit"`
+	Close           *CardText   `json:"close"`
+	PrivateMetadata string      `json:"private_metadata"`
+	Blocks          []CardBlock `json:"blocks"`
+}
+
+type EntrySubmission struct {
+	Channel     string
+	StandupName string
+	Kind        string
+	Title       string
+	Body        string
+	Date        string
+}
+
+type BodySubmission struct {
+	Channel   string
+	Entry     EntryRef
+	Timestamp string
+	Body      string
+}
+
+// ModalMetadata is what a modal needs to know about where it was opened when it's submitted, kept in its
+// private_metadata.
+type ModalMetadata struct {
+	Channel     string `json:"channel"`
+	StandupName string `json:"standup,omitempty"`
+	// Entry is the EntryRef of the entry being edited, or only its item id in forms opened before.
+	Entry       string `json:"item_id,omitempty"`
+	// Timestamp is the message of the entry's card, when the form was opened from it.
+	Timestamp   string `json:"ts,omitempty"`
+}
+
+func (metadata ModalMetadata) String() string {
+	metadataJson, _ := json.Marshal(metadata)
+	return string(metadataJson)
+}
+
+// ParseModalMetadata reads the private_metadata of a modal, where forms opened before it was kept as JSON only have the
+// channel.
+func ParseModalMetadata(privateMetadata string) (metadata ModalMetadata) {
+	if json.Unmarshal([]byte(privateMetadata), &metadata) != nil {
+		metadata = ModalMetadata{Channel: privateMetadata}
+	}
+	return
+}
+
+var entryKinds = []struct {
+	value       string
+	label       string
+	createEntry func(clock Clock, author string, title string, standup Standup) (entryType interface{})
+}{
+	{"faces", "Face", NewFace},
+	{"interestings", "Interesting", NewInteresting},
+	{"helps", "Help", NewHelp},
+	{"events", "Event", NewEvent},
+}
+
+// NewEntryModal is a form for a whole new entry, on the standup picked with `wb @name new` or the channel's default.
+func NewEntryModal(channel string, standupName string) EntryModal {
+	options := make([]CardOption, len(entryKinds))
+	for i, kind := range entryKinds {
+		options[i] = CardOption{Text: plainText(kind.label), Value: kind.value}
+	}
+
+	blocks := []CardBlock{
+		inputBlock(ENTRY_KIND_BLOCK, "Kind", CardElement{Type: "static_select", Placeholder: plainText("Pick a kind"), Options: options}),
+		inputBlock(ENTRY_TITLE_BLOCK, "Title", CardElement{Type: "plain_text_input"}),
+		inputBlock(ENTRY_BODY_BLOCK, "Body", CardElement{Type: "plain_text_input", Multiline: true}),
+		inputBlock(ENTRY_DATE_BLOCK, "Date", CardElement{Type: "datepicker", Placeholder: plainText("Today")}),
+	}
+	blocks[2].Optional = true
+	blocks[3].Optional = true
+
+	metadata := ModalMetadata{Channel: channel, StandupName: standupName}
+	return EntryModal{Type: "modal", CallbackId: NEW_ENTRY_CALLBACK, Title: plainText("New whiteboard entry"), Submit: plainText("Create"), Close: plainText("Cancel"), PrivateMetadata: metadata.String(), Blocks: blocks}
+}
+
+// NewEditBodyModal is a form for the entry's body, filled in with the body it has now.
+func NewEditBodyModal(channel string, ref EntryRef, timestamp string, body string) EntryModal {
+	block := inputBlock(ENTRY_BODY_BLOCK, "Body", CardElement{Type: "plain_text_input", Multiline: true, InitialValue: body})
+	block.Optional = true
+	metadata := ModalMetadata{Channel: channel, Entry: ref.String(), Timestamp: timestamp}
+	return EntryModal{Type: "modal", CallbackId: EDIT_BODY_CALLBACK, Title: plainText("Edit body"), Submit: plainText("Save"), Close: plainText("Cancel"), PrivateMetadata: metadata.String(), Blocks: []CardBlock{block}}
+}
+
+func inputBlock(blockId string, label string, element CardElement) CardBlock {
+	element.ActionId = blockId
+	return CardBlock{Type: "input", BlockId: blockId, Label: plainText(label), Element: &element}
+}
+
+func (whiteboard WhiteboardApp) handleNewCommand(_ string, ev *slack.MessageEvent, context CommandContext) {
+	if _, _, _, ok := whiteboard.getEntryDetails(ev, context); !ok {
+		return
+	}
+	slashClient, ok := whiteboard.SlackClient.(*SlashCommandClient)
+	if !ok || len(slashClient.TriggerId) == 0 {
+		whiteboard.SlackClient.PostReply("I can only open the new entry form from the slash command, try `/wb new`", ev.Channel, THUMBS_DOWN)
+		return
+	}
+	if !whiteboard.SlackClient.OpenView(slashClient.TriggerId, NewEntryModal(ev.Channel, context.StandupName)) {
+		whiteboard.SlackClient.PostReply("I couldn't open the new entry form, try again?", ev.Channel, THUMBS_DOWN)
+	}
+}
+
+// ValidateEntrySubmission checks the new entry form, returning any problems keyed by block id so they can be shown
+// next to the fields in the modal. Slack only waits a few seconds for them, so nothing is posted here.
+func ValidateEntrySubmission(submission EntrySubmission) (errors map[string]string) {
+	errors = make(map[string]string)
+	if entryKindCreator(submission.Kind) == nil {
+		errors[ENTRY_KIND_BLOCK] = "Pick what kind of entry this is"
+	}
+	if len(submission.Title) == 0 {
+		errors[ENTRY_TITLE_BLOCK] = "Oi! The title/name can't be empty!"
+	}
+	if len(submission.Body) > 0 && submission.Kind == "faces" {
+		errors[ENTRY_BODY_BLOCK] = "Face does not have a body!"
+	}
+	if _, err := time.Parse(DATE_FORMAT, submission.Date); len(submission.Date) > 0 && err != nil {
+		errors[ENTRY_DATE_BLOCK] = "Use YYYY-MM-DD as date format"
+	}
+	return
+}
+
+// HandleEntrySubmission creates the entry filled in on a new entry form that passed ValidateEntrySubmission.
+func (whiteboard WhiteboardApp) HandleEntrySubmission(submission EntrySubmission, user string) {
+	ev := &slack.MessageEvent{Msg: slack.Msg{Type: "message", User: user, Channel: submission.Channel}}
+	standup, slackUser, _, ok := whiteboard.getEntryDetails(ev, CommandContext{StandupName: submission.StandupName})
+	if !ok {
+		return
+	}
+	entryType := entryKindCreator(submission.Kind)(whiteboard.Clock, slackUser.Author, submission.Title, standup).(EntryType)
+	entry := entryType.GetEntry()
+	entry.Body = submission.Body
+	if len(submission.Date) > 0 {
+		entry.Date = submission.Date
+	}
+
+	whiteboard.validateAndPost(standup, entryType, ev)
+	whiteboard.Store.SetEntry(slackUser.Username, entryType)
+}
+
+// HandleBodySubmission sets the body of the entry from an edit body form, whoever submits it, leaving their own current
+// entry alone.
+func (whiteboard WhiteboardApp) HandleBodySubmission(submission BodySubmission, user string) {
+	ev := &slack.MessageEvent{Msg: slack.Msg{Type: "message", User: user, Channel: submission.Channel}}
+	if _, _, _, ok := whiteboard.getEntryDetails(ev, CommandContext{}); !ok {
+		return
+	}
+	defer whiteboard.lockEntry(submission.Entry.ItemId)()
+	standup, entryType, err := whiteboard.findEntry(ev.Channel, submission.Entry)
+	if err != nil {
+		handleRestError(whiteboard.SlackClient, err, ev.Channel)
+		return
+	}
+	if missingEntry(entryType) {
+		handleItemNotFound(whiteboard.SlackClient, submission.Entry.ItemId, ev.Channel)
+		return
+	}
+	if len(submission.Timestamp) > 0 {
+		whiteboard.rememberEntryMessage(standup.Backend, entryType.GetEntry(), EntryMessage{Channel: ev.Channel, Timestamp: submission.Timestamp})
+	}
+	whiteboard.handleUpdateBodyCommand(submission.Body, ev, CommandContext{Entry: entryType})
+}
+
+func entryKindCreator(value string) (createEntry func(clock Clock, author string, title string, standup Standup) (entryType interface{})) {
+	for _, kind := range entryKinds {
+		if kind.value == value {
+			createEntry = kind.createEntry
+		}
+	}
+	return
+}
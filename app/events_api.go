@@ -0,0 +1,119 @@
+package app
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/nlopes/slack"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+)
+
+const (
+	MAX_REQUEST_AGE = 5 * time.Minute
+)
+
+var leadingMention = regexp.MustCompile("^\\s*<@[a-zA-Z0-9]+>[:,]?\\s*")
+
+type EventsHandler struct {
+	SigningSecret string
+	Clock         Clock
+	Handle        func(ev *slack.MessageEvent)
+}
+
+type eventsPayload struct {
+	Type      string     `json:"type"`
+	Challenge string     `json:"challenge"`
+	Event     eventsItem `json:"event"`
+}
+
+type eventsItem struct {
+	Type            string `json:"type"`
+	SubType         string `json:"subtype"`
+	BotId           string `json:"bot_id"`
+	User            string `json:"user"`
+	Text            string `json:"text"`
+	Channel         string `json:"channel"`
+	Timestamp       string `json:"ts"`
+	ThreadTimestamp string `json:"thread_ts"`
+}
+
+func (handler EventsHandler) ServeHTTP(responseWriter http.ResponseWriter, req *http.Request) {
+	body, ok := VerifySlackRequest(handler.SigningSecret, handler.Clock, req)
+	if !ok {
+		http.Error(responseWriter, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+
+	var payload eventsPayload
+	if err := json.Unmarshal(body, &payload); err != nil {
+		http.Error(responseWriter, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	switch payload.Type {
+	case "url_verification":
+		responseWriter.Header().Set("Content-Type", "text/plain")
+		fmt.Fprint(responseWriter, payload.Challenge)
+	case "event_callback":
+		if ev, ok := toMessageEvent(payload.Event); ok {
+			handler.Handle(ev)
+		}
+		responseWriter.WriteHeader(http.StatusOK)
+	default:
+		responseWriter.WriteHeader(http.StatusOK)
+	}
+}
+
+func toMessageEvent(event eventsItem) (ev *slack.MessageEvent, ok bool) {
+	if len(event.BotId) > 0 || len(event.SubType) > 0 {
+		return
+	}
+
+	text := event.Text
+	switch event.Type {
+	case "message":
+	case "app_mention":
+		text = leadingMention.ReplaceAllString(text, "")
+		if keyword, _ := readNextCommand(text); !matches(keyword, "wb") {
+			text = "wb " + text
+		}
+	default:
+		return
+	}
+
+	ev = &slack.MessageEvent{Msg: slack.Msg{Type: "message", User: event.User, Text: text, Channel: event.Channel, Timestamp: event.Timestamp, ThreadTimestamp: event.ThreadTimestamp}}
+	return ev, true
+}
+
+// VerifySlackRequest checks the request was signed by Slack with the app's signing secret, and returns the request body.
+func VerifySlackRequest(signingSecret string, clock Clock, req *http.Request) (body []byte, ok bool) {
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil || len(signingSecret) == 0 {
+		return
+	}
+
+	timestamp := req.Header.Get("X-Slack-Request-Timestamp")
+	seconds, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || math.Abs(clock.Now().Sub(time.Unix(seconds, 0)).Seconds()) > MAX_REQUEST_AGE.Seconds() {
+		return
+	}
+
+	expected := SignSlackRequest(signingSecret, timestamp, body)
+	ok = hmac.Equal([]byte(expected), []byte(req.Header.Get("X-Slack-Signature")))
+	return
+}
+
+func SignSlackRequest(signingSecret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(signingSecret))
+	mac.Write([]byte("v0:" + timestamp + ":"))
+	mac.Write(body)
+	return "v0=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,111 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/nlopes/slack"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+)
+
+const (
+	HISTORY_TIME_FORMAT = "02 Jan 2006 15:04"
+)
+
+func (whiteboard WhiteboardApp) recordChange(standup Standup, slackUser SlackUser, entry *Entry, before EntryFields) {
+	after := FieldsOf(entry)
+	if after == before {
+		return
+	}
+	history, _ := whiteboard.Store.GetEntryHistory(standup.Backend, entry.Id)
+	history.Record(EntryChange{Author: slackUser.Author, Time: whiteboard.Clock.Now(), Before: before, After: after})
+	whiteboard.Store.SetEntryHistory(standup.Backend, entry.Id, history)
+}
+
+// handleUndoCommand deletes the entries from the user's last `wb bulk` in the channel if they haven't touched their
+// entry since, and takes back the last change to their entry otherwise.
+func (whiteboard WhiteboardApp) handleUndoCommand(_ string, ev *slack.MessageEvent, context CommandContext) {
+	standup, slackUser, entryType, ok := whiteboard.getEntryDetails(ev, context)
+	if !ok {
+		return
+	}
+	if bulk, ok := whiteboard.Store.GetBulkEntries(ev.Channel, slackUser.Username); ok && bulk.Entry == entrySnapshot(entryType) {
+		whiteboard.rollBackBulk(bulk, slackUser, entryType, ev.Channel)
+		return
+	}
+	whiteboard.moveInHistory(standup, slackUser, entryType, ev, true)
+}
+
+func (whiteboard WhiteboardApp) handleRedoCommand(_ string, ev *slack.MessageEvent, context CommandContext) {
+	standup, slackUser, entryType, ok := whiteboard.getEntryDetails(ev, context)
+	if !ok {
+		return
+	}
+	whiteboard.moveInHistory(standup, slackUser, entryType, ev, false)
+}
+
+// moveInHistory takes back the last change to the user's entry, or makes the last undone change again, and only moves
+// through the history once the whiteboard has the change.
+func (whiteboard WhiteboardApp) moveInHistory(standup Standup, slackUser SlackUser, entryType EntryType, ev *slack.MessageEvent, undo bool) {
+	if missingEntry(entryType) || len(entryType.GetEntry().Id) == 0 {
+		handleMissingEntry(whiteboard.SlackClient, ev.Channel)
+		return
+	}
+	entry := entryType.GetEntry()
+	standup = whiteboard.entryStandup(ev.Channel, entryType, standup)
+	defer whiteboard.lockEntry(entry.Id)()
+	history, _ := whiteboard.Store.GetEntryHistory(standup.Backend, entry.Id)
+
+	command, fields, ok := "undo", EntryFields{}, false
+	if undo {
+		fields, ok = history.Undo()
+	} else {
+		command = "redo"
+		fields, ok = history.Redo()
+	}
+	if !ok {
+		whiteboard.SlackClient.PostReply(fmt.Sprintf("There's nothing to %v for this entry.", command), ev.Channel, THUMBS_DOWN)
+		return
+	}
+
+	fields.ApplyTo(entry)
+	if !whiteboard.validateAndPost(standup, entryType, ev) {
+		return
+	}
+	if undo {
+		history.Undone()
+	} else {
+		history.Redone()
+	}
+	whiteboard.Store.SetEntryHistory(standup.Backend, entry.Id, history)
+	whiteboard.Store.SetEntry(slackUser.Username, entryType)
+}
+
+func (whiteboard WhiteboardApp) handleHistoryCommand(_ string, ev *slack.MessageEvent, context CommandContext) {
+	standup, _, entryType, ok := whiteboard.getEntryDetails(ev, context)
+	if !ok {
+		return
+	}
+	if missingEntry(entryType) || len(entryType.GetEntry().Id) == 0 {
+		handleMissingEntry(whiteboard.SlackClient, ev.Channel)
+		return
+	}
+	entry := entryType.GetEntry()
+	standup = whiteboard.entryStandup(ev.Channel, entryType, standup)
+	history, _ := whiteboard.Store.GetEntryHistory(standup.Backend, entry.Id)
+	if len(history.Changes) == 0 {
+		whiteboard.SlackClient.PostReply(fmt.Sprintf("*%v* hasn't been changed since it was created.", entry.Title), ev.Channel, "")
+		return
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("Changes to *%v*:\n", entry.Title))
+	for i, change := range history.Changes {
+		undone := ""
+		if i >= history.Position {
+			undone = " _(undone)_"
+		}
+		buffer.WriteString(fmt.Sprintf("\n`#%v` %v [%v] %v%v", i + 1, change.Time.In(standup.Location()).Format(HISTORY_TIME_FORMAT), change.Author, change.Describe(), undone))
+	}
+	buffer.WriteString("\n\nTake back the last change with `wb undo`, or make an undone one again with `wb redo`")
+	whiteboard.SlackClient.PostReply(buffer.String(), ev.Channel, "")
+}
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"encoding/json"
+	"github.com/nlopes/slack"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type InteractivityHandler struct {
+	SigningSecret string
+	Clock         Clock
+	Whiteboard    WhiteboardApp
+	Dispatcher    *Dispatcher
+}
+
+type EntryAction struct {
+	ActionId     string
+	Entry        EntryRef
+	SelectedDate string
+	Timestamp    string
+}
+
+type interactionPayload struct {
+	Type        string `json:"type"`
+	ResponseUrl string `json:"response_url"`
+	TriggerId   string `json:"trigger_id"`
+	User        struct {
+		Id string `json:"id"`
+	} `json:"user"`
+	Channel struct {
+		Id string `json:"id"`
+	} `json:"channel"`
+	Message struct {
+		Timestamp   string           `json:"ts"`
+		Attachments []CardAttachment `json:"attachments"`
+	} `json:"message"`
+	View struct {
+		CallbackId      string `json:"callback_id"`
+		PrivateMetadata string `json:"private_metadata"`
+		State           struct {
+			Values map[string]map[string]struct {
+				Value          string `json:"value"`
+				SelectedDate   string `json:"selected_date"`
+				SelectedOption struct {
+					Value string `json:"value"`
+				} `json:"selected_option"`
+			} `json:"values"`
+		} `json:"state"`
+	} `json:"view"`
+	Actions []struct {
+		ActionId     string `json:"action_id"`
+		BlockId      string `json:"block_id"`
+		SelectedDate string `json:"selected_date"`
+	} `json:"actions"`
+}
+
+func (handler InteractivityHandler) ServeHTTP(responseWriter http.ResponseWriter, req *http.Request) {
+	body, ok := VerifySlackRequest(handler.SigningSecret, handler.Clock, req)
+	if !ok {
+		http.Error(responseWriter, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(responseWriter, "invalid payload", http.StatusBadRequest)
+		return
+	}
+	var payload interactionPayload
+	if err := json.Unmarshal([]byte(form.Get("payload")), &payload); err != nil {
+		http.Error(responseWriter, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	if payload.Type == "view_submission" && payload.View.CallbackId == NEW_ENTRY_CALLBACK {
+		handler.handleEntrySubmission(responseWriter, payload)
+		return
+	}
+	if payload.Type == "view_submission" && payload.View.CallbackId == EDIT_BODY_CALLBACK {
+		handler.handleBodySubmission(responseWriter, payload)
+		return
+	}
+	if payload.Type == "block_actions" {
+		ev := &slack.MessageEvent{Msg: slack.Msg{Type: "message", User: payload.User.Id, Channel: payload.Channel.Id}}
+		whiteboard := handler.Whiteboard.WithSlackClient(&SlashCommandClient{SlackClient: handler.Whiteboard.SlackClient, ResponseUrl: payload.ResponseUrl})
+		for _, action := range payload.Actions {
+			if action.ActionId == EDIT_BODY_ACTION {
+				whiteboard.openEditBody(payload.TriggerId, ParseEntryRef(action.BlockId), payload.Message.Timestamp, CardBody(payload.Message.Attachments), ev.Channel)
+				continue
+			}
+			entryAction := EntryAction{ActionId: action.ActionId, Entry: ParseEntryRef(action.BlockId), SelectedDate: action.SelectedDate, Timestamp: payload.Message.Timestamp}
+			if handler.Dispatcher != nil {
+				handler.Dispatcher.DispatchTask(ev.User, func() {
+					whiteboard.HandleEntryAction(entryAction, ev)
+				})
+			} else {
+				whiteboard.HandleEntryAction(entryAction, ev)
+			}
+		}
+	}
+	responseWriter.WriteHeader(http.StatusOK)
+}
+
+func (handler InteractivityHandler) handleEntrySubmission(responseWriter http.ResponseWriter, payload interactionPayload) {
+	values := payload.View.State.Values
+	metadata := ParseModalMetadata(payload.View.PrivateMetadata)
+	submission := EntrySubmission{
+		Channel:     metadata.Channel,
+		StandupName: metadata.StandupName,
+		Kind:        values[ENTRY_KIND_BLOCK][ENTRY_KIND_BLOCK].SelectedOption.Value,
+		Title:       strings.TrimSpace(values[ENTRY_TITLE_BLOCK][ENTRY_TITLE_BLOCK].Value),
+		Body:        strings.TrimSpace(values[ENTRY_BODY_BLOCK][ENTRY_BODY_BLOCK].Value),
+		Date:        values[ENTRY_DATE_BLOCK][ENTRY_DATE_BLOCK].SelectedDate,
+	}
+
+	if errors := ValidateEntrySubmission(submission); len(errors) > 0 {
+		responseWriter.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(responseWriter).Encode(map[string]interface{}{"response_action": "errors", "errors": errors})
+		return
+	}
+	responseWriter.WriteHeader(http.StatusOK)
+
+	if handler.Dispatcher != nil {
+		handler.Dispatcher.DispatchTask(payload.User.Id, func() {
+			handler.Whiteboard.HandleEntrySubmission(submission, payload.User.Id)
+		})
+	} else {
+		handler.Whiteboard.HandleEntrySubmission(submission, payload.User.Id)
+	}
+}
+
+func (handler InteractivityHandler) handleBodySubmission(responseWriter http.ResponseWriter, payload interactionPayload) {
+	metadata := ParseModalMetadata(payload.View.PrivateMetadata)
+	submission := BodySubmission{
+		Channel:   metadata.Channel,
+		Entry:     ParseEntryRef(metadata.Entry),
+		Timestamp: metadata.Timestamp,
+		Body:      strings.TrimSpace(payload.View.State.Values[ENTRY_BODY_BLOCK][ENTRY_BODY_BLOCK].Value),
+	}
+	responseWriter.WriteHeader(http.StatusOK)
+
+	if handler.Dispatcher != nil {
+		handler.Dispatcher.DispatchTask(payload.User.Id, func() {
+			handler.Whiteboard.HandleBodySubmission(submission, payload.User.Id)
+		})
+	} else {
+		handler.Whiteboard.HandleBodySubmission(submission, payload.User.Id)
+	}
+}
+
+// openEditBody opens the edit body form straight away with the body on the card, as the trigger id expires in 3 seconds.
+// The entry is only looked up once the form is submitted.
+func (whiteboard WhiteboardApp) openEditBody(triggerId string, ref EntryRef, timestamp string, body string, channel string) {
+	if !whiteboard.SlackClient.OpenView(triggerId, NewEditBodyModal(channel, ref, timestamp, body)) {
+		whiteboard.SlackClient.PostReply("I couldn't open the edit body form, try again?", channel, THUMBS_DOWN)
+	}
+}
+
+// HandleEntryAction routes a button pressed on an entry card to the same handlers as the matching `wb` command, for
+// the card's entry. The presser's own current entry is only updated when it's the card's entry.
+func (whiteboard WhiteboardApp) HandleEntryAction(action EntryAction, ev *slack.MessageEvent) {
+	_, slackUser, currentEntry, ok := whiteboard.getEntryDetails(ev, CommandContext{})
+	if !ok {
+		return
+	}
+	defer whiteboard.lockEntry(action.Entry.ItemId)()
+	standup, entryType, err := whiteboard.findEntry(ev.Channel, action.Entry)
+	if err != nil {
+		handleRestError(whiteboard.SlackClient, err, ev.Channel)
+		return
+	}
+	if missingEntry(entryType) {
+		handleItemNotFound(whiteboard.SlackClient, action.Entry.ItemId, ev.Channel)
+		return
+	}
+	entry := entryType.GetEntry()
+	whiteboard.rememberEntryMessage(standup.Backend, entry, EntryMessage{Channel: ev.Channel, Timestamp: action.Timestamp})
+
+	if action.ActionId == DELETE_ACTION {
+		if whiteboard.canDelete(standup, slackUser, entry, ev) {
+			whiteboard.deleteItem(standup, slackUser, currentEntry, entry.Id, ev.Channel)
+		}
+		return
+	}
+
+	context := CommandContext{Entry: entryType}
+	switch action.ActionId {
+	case CHANGE_DATE_ACTION:
+		whiteboard.handleUpdateDateCommand(action.SelectedDate, ev, context)
+	case MAKE_PUBLIC_ACTION:
+		whiteboard.handlePublicCommand("", ev, context)
+	case MAKE_PRIVATE_ACTION:
+		whiteboard.handlePrivateCommand("", ev, context)
+	}
+}
@@ -0,0 +1,153 @@
+package app
+
+import (
+	"encoding/json"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"sort"
+	"time"
+)
+
+// keyValueStore is what a storage backend provides, with the rest of the Store built on top by jsonStore.
+type keyValueStore interface {
+	Get(key string) (value string, ok bool)
+	Set(key string, value string)
+	SetExpiring(key string, value string, expiry time.Duration)
+	Keys(prefix string) (keys []string)
+	SetIfMissing(key string, value string, expiry time.Duration) (ok bool)
+}
+
+// storedValue is a value kept by the stores that expire values themselves, where a zero Expires never expires.
+type storedValue struct {
+	Value   string    `json:"value"`
+	Expires time.Time `json:"expires"`
+}
+
+func (value storedValue) expired(now time.Time) bool {
+	return !value.Expires.IsZero() && !now.Before(value.Expires)
+}
+
+func expiringValue(clock Clock, value string, expiry time.Duration) storedValue {
+	if expiry <= 0 {
+		return storedValue{Value: value}
+	}
+	return storedValue{Value: value, Expires: clock.Now().Add(expiry)}
+}
+
+// jsonStore keeps the standups, entries and the rest as JSON under their keys in a keyValueStore.
+type jsonStore struct {
+	values keyValueStore
+}
+
+func (store jsonStore) getJson(key string, value interface{}) (ok bool) {
+	valueJson, ok := store.values.Get(key)
+	if !ok {
+		return
+	}
+	return json.Unmarshal([]byte(valueJson), value) == nil
+}
+
+func (store jsonStore) setJson(key string, value interface{}) {
+	valueJson, _ := json.Marshal(value)
+	store.values.Set(key, string(valueJson))
+}
+
+func (store jsonStore) GetStandup(channel string) (standup Standup, ok bool) {
+	ok = store.getJson(StandupKey(channel), &standup)
+	return
+}
+
+func (store jsonStore) SetStandup(channel string, standup Standup) {
+	store.setJson(StandupKey(channel), standup)
+}
+
+func (store jsonStore) SetStandupIfMissing(channel string, standup Standup) (ok bool) {
+	standupJson, _ := json.Marshal(standup)
+	return store.values.SetIfMissing(StandupKey(channel), string(standupJson), 0)
+}
+
+// GetNamedStandups lists the channel's named standups in the order of their aliases.
+func (store jsonStore) GetNamedStandups(channel string) (standups []Standup, ok bool) {
+	keys := store.values.Keys(NamedStandupKey(channel, ""))
+	sort.Strings(keys)
+	for _, key := range keys {
+		var standup Standup
+		if store.getJson(key, &standup) {
+			standups = append(standups, standup)
+		}
+	}
+	return standups, len(standups) > 0
+}
+
+func (store jsonStore) SetNamedStandup(channel string, standup Standup) {
+	store.setJson(NamedStandupKey(channel, standup.Alias), standup)
+}
+
+func (store jsonStore) GetEntry(username string) (entryType EntryType, ok bool) {
+	entryJson, ok := store.values.Get(EntryKey(username))
+	if !ok {
+		return
+	}
+	return UnmarshalEntry(entryJson)
+}
+
+func (store jsonStore) SetEntry(username string, entryType EntryType) {
+	store.values.SetExpiring(EntryKey(username), MarshalEntry(entryType), ENTRY_EXPIRY)
+}
+
+func (store jsonStore) GetEntryMessage(backend string, itemId string) (message EntryMessage, ok bool) {
+	ok = store.getJson(EntryMessageKey(backend, itemId), &message)
+	return
+}
+
+func (store jsonStore) SetEntryMessage(backend string, itemId string, message EntryMessage) {
+	messageJson, _ := json.Marshal(message)
+	store.values.SetExpiring(EntryMessageKey(backend, itemId), string(messageJson), ENTRY_EXPIRY)
+}
+
+func (store jsonStore) GetSchedule(channel string, alias string) (schedule Schedule, ok bool) {
+	ok = store.getJson(ScheduleKey(channel, alias), &schedule)
+	return
+}
+
+func (store jsonStore) SetSchedule(channel string, alias string, schedule Schedule) {
+	store.setJson(ScheduleKey(channel, alias), schedule)
+}
+
+func (store jsonStore) GetEntryHistory(backend string, itemId string) (history EntryHistory, ok bool) {
+	ok = store.getJson(HistoryKey(backend, itemId), &history)
+	return
+}
+
+func (store jsonStore) SetEntryHistory(backend string, itemId string, history EntryHistory) {
+	historyJson, _ := json.Marshal(history)
+	store.values.SetExpiring(HistoryKey(backend, itemId), string(historyJson), ENTRY_EXPIRY)
+}
+
+func (store jsonStore) GetPendingDelete(channel string, username string) (pending PendingDelete, ok bool) {
+	ok = store.getJson(PendingDeleteKey(channel, username), &pending)
+	return
+}
+
+func (store jsonStore) SetPendingDelete(channel string, username string, pending PendingDelete) {
+	pendingJson, _ := json.Marshal(pending)
+	store.values.SetExpiring(PendingDeleteKey(channel, username), string(pendingJson), CONFIRMATION_EXPIRY)
+}
+
+func (store jsonStore) GetBulkEntries(channel string, username string) (bulk BulkEntries, ok bool) {
+	ok = store.getJson(BulkKey(channel, username), &bulk)
+	return
+}
+
+func (store jsonStore) SetBulkEntries(channel string, username string, bulk BulkEntries) {
+	bulkJson, _ := json.Marshal(bulk)
+	store.values.SetExpiring(BulkKey(channel, username), string(bulkJson), ENTRY_EXPIRY)
+}
+
+func (store jsonStore) GetArchivedPost(channel string) (post ArchivedPost, ok bool) {
+	ok = store.getJson(PostKey(channel), &post)
+	return
+}
+
+func (store jsonStore) SetArchivedPost(channel string, post ArchivedPost) {
+	store.setJson(PostKey(channel), post)
+}
@@ -0,0 +1,115 @@
+package app
+
+import (
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	EXPIRY_SWEEP_INTERVAL = time.Hour
+)
+
+// MemoryStore keeps everything in memory, for running the bot without Redis. Nothing survives a restart. Expired
+// values are never returned, and are swept out every EXPIRY_SWEEP_INTERVAL.
+type MemoryStore struct {
+	jsonStore
+	Clock     Clock
+	values    map[string]storedValue
+	nextSweep time.Time
+	mutex     sync.RWMutex
+}
+
+func NewMemoryStore(clock Clock) *MemoryStore {
+	store := &MemoryStore{Clock: clock, values: make(map[string]storedValue)}
+	store.jsonStore = jsonStore{store}
+	return store
+}
+
+func (store *MemoryStore) Get(key string) (value string, ok bool) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	stored, ok := store.values[key]
+	if !ok || stored.expired(store.Clock.Now()) {
+		return "", false
+	}
+	return stored.Value, true
+}
+
+func (store *MemoryStore) Set(key string, value string) {
+	store.SetExpiring(key, value, 0)
+}
+
+func (store *MemoryStore) SetExpiring(key string, value string, expiry time.Duration) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	store.sweepExpired()
+	store.values[key] = expiringValue(store.Clock, value, expiry)
+}
+
+func (store *MemoryStore) Delete(key string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	delete(store.values, key)
+}
+
+func (store *MemoryStore) SetIfMissing(key string, value string, expiry time.Duration) (ok bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	if stored, found := store.values[key]; found && !stored.expired(store.Clock.Now()) {
+		return false
+	}
+	store.values[key] = expiringValue(store.Clock, value, expiry)
+	return true
+}
+
+func (store *MemoryStore) Take(key string) (value string, ok bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+
+	stored, ok := store.values[key]
+	delete(store.values, key)
+	if !ok || stored.expired(store.Clock.Now()) {
+		return "", false
+	}
+	return stored.Value, true
+}
+
+func (store *MemoryStore) Keys(prefix string) (keys []string) {
+	store.mutex.RLock()
+	defer store.mutex.RUnlock()
+
+	now := store.Clock.Now()
+	for key, stored := range store.values {
+		if strings.HasPrefix(key, prefix) && !stored.expired(now) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return
+}
+
+// SchemaVersion is always the current one, as nothing older can be in memory.
+func (store *MemoryStore) SchemaVersion() int {
+	return SCHEMA_VERSION
+}
+
+// sweepExpired deletes the expired values, at most once every EXPIRY_SWEEP_INTERVAL. The caller holds the lock.
+func (store *MemoryStore) sweepExpired() {
+	now := store.Clock.Now()
+	if now.Before(store.nextSweep) {
+		return
+	}
+	for key, stored := range store.values {
+		if stored.expired(now) {
+			delete(store.values, key)
+		}
+	}
+	store.nextSweep = now.Add(EXPIRY_SWEEP_INTERVAL)
+}
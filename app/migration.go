@@ -0,0 +1,213 @@
+package app
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/garyburd/redigo/redis"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"go.etcd.io/bbolt"
+	"strconv"
+	"strings"
+)
+
+const (
+	LEGACY_ENTRY_PREFIX = "entry:"
+	V2_KEY_NAMESPACE    = "wb:v2:"
+)
+
+// keyMigration is what becomes of a key written by an earlier schema: it's moved to key, or replaced by values when
+// it's split up. With neither, it's dropped.
+type keyMigration struct {
+	key    string
+	values map[string]string
+}
+
+// Migrate moves the keys written by earlier versions of the bot to the current schema, keeping their expiry. Keys that
+// already exist in the current schema are left alone, so it's safe to run on every start.
+func (store *RealStore) Migrate() (migrated int, err error) {
+	if store.SchemaVersion() >= SCHEMA_VERSION {
+		return
+	}
+	conn := store.Pool.Get()
+	defer conn.Close()
+
+	for _, key := range store.Keys("") {
+		migration, ok := migrateKey(key, store.Get)
+		if !ok {
+			continue
+		}
+		moved, err := migration.applyToRedis(conn, key)
+		if err != nil {
+			return migrated, fmt.Errorf("couldn't migrate %v: %v", key, err)
+		}
+		migrated += moved
+	}
+	if _, err = conn.Do("SET", SCHEMA_VERSION_KEY, strconv.Itoa(SCHEMA_VERSION)); err != nil {
+		return migrated, fmt.Errorf("couldn't set the schema version: %v", err)
+	}
+	return
+}
+
+// Migrate moves the keys written by earlier versions of the bot to the current schema, as RealStore's does, all in one
+// update.
+func (store *BoltStore) Migrate() (migrated int, err error) {
+	if store.SchemaVersion() >= SCHEMA_VERSION {
+		return
+	}
+	err = store.DB.Update(func(tx *bbolt.Tx) error {
+		bucket := tx.Bucket([]byte(BOLT_BUCKET))
+		get := func(key string) (string, bool) {
+			stored, found, _ := getValue(bucket, []byte(key))
+			return stored.Value, found
+		}
+		var keys []string
+		bucket.ForEach(func(key []byte, _ []byte) error {
+			keys = append(keys, string(key))
+			return nil
+		})
+		for _, key := range keys {
+			migration, ok := migrateKey(key, get)
+			if !ok {
+				continue
+			}
+			moved, err := migration.applyToBolt(bucket, key)
+			if err != nil {
+				return fmt.Errorf("couldn't migrate %v: %v", key, err)
+			}
+			migrated += moved
+		}
+		return putValue(bucket, SCHEMA_VERSION_KEY, storedValue{Value: strconv.Itoa(SCHEMA_VERSION)})
+	})
+	return
+}
+
+// migrateKey works out what becomes of a key written by an earlier schema, looking its value up with get when that
+// decides it. Keys that don't look like the bot's are left alone.
+//
+// Schema 1 only ever kept a channel's standup under the bare channel id, and a user's entry under `entry:<username>`.
+// Schema 2 kept everything under wb:v2:, with a channel's named standups in one list, and its schedules among the
+// channel's keys along with a list of the scheduled channels.
+func migrateKey(key string, get func(key string) (string, bool)) (migration keyMigration, ok bool) {
+	if strings.HasPrefix(key, V2_KEY_NAMESPACE) {
+		return migrateV2Key(key, get)
+	}
+	if strings.HasPrefix(key, "wb:") {
+		return
+	}
+	if newKey, ok := legacyEntryKey(key, get); ok {
+		return keyMigration{key: newKey}, true
+	}
+	if newKey, ok := legacyStandupKey(key, get); ok {
+		return keyMigration{key: newKey}, true
+	}
+	return
+}
+
+func migrateV2Key(key string, get func(key string) (string, bool)) (migration keyMigration, ok bool) {
+	rest := strings.TrimPrefix(key, V2_KEY_NAMESPACE)
+	if rest == "schedules" {
+		return keyMigration{}, true
+	}
+	parts := strings.SplitN(rest, ":", 3)
+	if len(parts) == 3 && parts[0] == "channel" {
+		channel, kind := parts[1], parts[2]
+		switch {
+		case kind == "standups":
+			return namedStandupsMigration(channel, key, get)
+		case kind == "schedule":
+			return keyMigration{key: ScheduleKey(channel, "")}, true
+		case strings.HasPrefix(kind, "schedule:"):
+			return keyMigration{key: ScheduleKey(channel, strings.TrimPrefix(kind, "schedule:"))}, true
+		}
+	}
+	return keyMigration{key: KEY_NAMESPACE + rest}, true
+}
+
+// namedStandupsMigration splits a channel's list of named standups up into a key each.
+func namedStandupsMigration(channel string, key string, get func(key string) (string, bool)) (migration keyMigration, ok bool) {
+	value, ok := get(key)
+	var standups []Standup
+	if !ok || json.Unmarshal([]byte(value), &standups) != nil {
+		return keyMigration{}, false
+	}
+	migration.values = make(map[string]string)
+	for _, standup := range standups {
+		standupJson, _ := json.Marshal(standup)
+		migration.values[NamedStandupKey(channel, standup.Alias)] = string(standupJson)
+	}
+	return migration, true
+}
+
+// legacyStandupKey finds the standups the first versions of the bot kept under the bare channel id.
+func legacyStandupKey(key string, get func(key string) (string, bool)) (newKey string, ok bool) {
+	if strings.Contains(key, ":") {
+		return
+	}
+	value, ok := get(key)
+	var standup Standup
+	if !ok || json.Unmarshal([]byte(value), &standup) != nil || standup.Id == 0 {
+		return "", false
+	}
+	return StandupKey(key), true
+}
+
+// legacyEntryKey finds the entries the first versions of the bot kept under `entry:<username>`.
+func legacyEntryKey(key string, get func(key string) (string, bool)) (newKey string, ok bool) {
+	username := strings.TrimPrefix(key, LEGACY_ENTRY_PREFIX)
+	if username == key || len(username) == 0 || strings.Contains(username, ":") {
+		return
+	}
+	value, ok := get(key)
+	if !ok {
+		return
+	}
+	if _, ok = UnmarshalEntry(value); !ok {
+		return "", false
+	}
+	return EntryKey(username), true
+}
+
+// applyToRedis renames the key with RENAMENX, which keeps its expiry, or sets the values it's split into.
+func (migration keyMigration) applyToRedis(conn redis.Conn, key string) (migrated int, err error) {
+	if len(migration.key) > 0 {
+		renamed, err := redis.Bool(conn.Do("RENAMENX", key, migration.key))
+		if renamed {
+			migrated++
+		}
+		return migrated, err
+	}
+	for newKey, value := range migration.values {
+		set, err := redis.Bool(conn.Do("SETNX", newKey, value))
+		if err != nil {
+			return migrated, err
+		}
+		if set {
+			migrated++
+		}
+	}
+	_, err = conn.Do("DEL", key)
+	return
+}
+
+// applyToBolt moves the key along with its expiry, or sets the values it's split into.
+func (migration keyMigration) applyToBolt(bucket *bbolt.Bucket, key string) (migrated int, err error) {
+	if len(migration.key) > 0 {
+		if bucket.Get([]byte(migration.key)) != nil {
+			return
+		}
+		if err = bucket.Put([]byte(migration.key), append([]byte{}, bucket.Get([]byte(key))...)); err != nil {
+			return
+		}
+		return 1, bucket.Delete([]byte(key))
+	}
+	for newKey, value := range migration.values {
+		if bucket.Get([]byte(newKey)) != nil {
+			continue
+		}
+		if err = putValue(bucket, newKey, storedValue{Value: value}); err != nil {
+			return
+		}
+		migrated++
+	}
+	return migrated, bucket.Delete([]byte(key))
+}
@@ -4,21 +4,97 @@ import (
 	"github.com/garyburd/redigo/redis"
 	"os"
 	"fmt"
+	"github.com/pivotal-sydney/whiteboardbot/config"
 	. "github.com/pivotal-sydney/whiteboardbot/model"
-	"encoding/json"
+	"strings"
+	"time"
 )
 
+const (
+	ENTRY_EXPIRY = 7 * 24 * time.Hour
+	CONFIRMATION_EXPIRY = 15 * time.Minute
+	SEEN_EXPIRY = 10 * time.Minute
+	SCHEMA_VERSION = 3
+	SCHEMA_VERSION_KEY = "wb:schema_version"
+	KEY_NAMESPACE = "wb:v3:"
+	SCHEDULE_KEY_PREFIX = KEY_NAMESPACE + "schedule:"
+	SCAN_COUNT = 100
+)
+
+var globEscaper = strings.NewReplacer("\\", "\\\\", "*", "\\*", "?", "\\?", "[", "\\[", "]", "\\]")
+
 type Store interface {
 	Get(key string) (value string, ok bool)
 	Set(key string, value string)
+	SetExpiring(key string, value string, expiry time.Duration)
+	Delete(key string)
+	Keys(prefix string) (keys []string)
+	SetIfMissing(key string, value string, expiry time.Duration) (ok bool)
+	Take(key string) (value string, ok bool)
+	SchemaVersion() int
 	GetStandup(channel string) (standup Standup, ok bool)
 	SetStandup(channel string, standup Standup)
+	SetStandupIfMissing(channel string, standup Standup) (ok bool)
+	GetNamedStandups(channel string) (standups []Standup, ok bool)
+	SetNamedStandup(channel string, standup Standup)
+	GetEntry(username string) (entryType EntryType, ok bool)
+	SetEntry(username string, entryType EntryType)
+	GetEntryMessage(backend string, itemId string) (message EntryMessage, ok bool)
+	SetEntryMessage(backend string, itemId string, message EntryMessage)
+	GetSchedule(channel string, alias string) (schedule Schedule, ok bool)
+	SetSchedule(channel string, alias string, schedule Schedule)
+	GetEntryHistory(backend string, itemId string) (history EntryHistory, ok bool)
+	SetEntryHistory(backend string, itemId string, history EntryHistory)
+	GetPendingDelete(channel string, username string) (pending PendingDelete, ok bool)
+	SetPendingDelete(channel string, username string, pending PendingDelete)
+	GetBulkEntries(channel string, username string) (bulk BulkEntries, ok bool)
+	SetBulkEntries(channel string, username string, bulk BulkEntries)
+	GetArchivedPost(channel string) (post ArchivedPost, ok bool)
+	SetArchivedPost(channel string, post ArchivedPost)
+}
+
+// EntryMessage is where an entry's card was posted, along with the fields it shows, which are the entry as the bot last
+// saw it from anywhere.
+type EntryMessage struct {
+	Channel   string       `json:"channel"`
+	Timestamp string       `json:"ts"`
+	Fields    *EntryFields `json:"fields,omitempty"`
+}
+
+// PendingDelete is an item waiting for `wb delete yes`, with the standup it's on.
+type PendingDelete struct {
+	ItemId  string  `json:"item_id"`
+	Standup Standup `json:"standup"`
 }
 
+// BulkEntries are the items created by a user's last `wb bulk` in a channel, with the standup they're on, so
+// `wb undo` can delete them again. Entry is the user's current entry as it was then, to tell whether they've done
+// anything since.
+type BulkEntries struct {
+	ItemIds []string `json:"item_ids"`
+	Standup Standup  `json:"standup"`
+	Entry   string   `json:"entry,omitempty"`
+}
+
+// ArchivedPost is the channel's latest post from `wb archive`, with the standup it was archived from, so `wb email`
+// sends it from the same one.
+type ArchivedPost struct {
+	PostId  string  `json:"post_id"`
+	Standup Standup `json:"standup"`
+}
+
+// RealStore keeps everything in Redis.
 type RealStore struct{
+	jsonStore
 	Pool *redis.Pool
 }
 
+func NewRealStore(pool *redis.Pool) *RealStore {
+	store := &RealStore{Pool: pool}
+	store.jsonStore = jsonStore{store}
+	return store
+}
+
 func NewPool() *redis.Pool {
 	return &redis.Pool{
 		MaxIdle: 10,
@@ -29,26 +105,111 @@ func NewPool() *redis.Pool {
 	}
 }
 
-func (store *RealStore) GetStandup(channel string) (standup Standup, ok bool) {
-	var standupJson string
-	standupJson, _ = store.Get(channel)
-	err := json.Unmarshal([]byte(standupJson), &standup)
-	ok = err == nil
-	return
+// Keys are namespaced by the schema version and what they belong to, like `wb:v3:channel:<id>:standup`.
+func channelKey(channel string, kind string) string {
+	return KEY_NAMESPACE + "channel:" + channel + ":" + kind
+}
+
+func userKey(username string, kind string) string {
+	return KEY_NAMESPACE + "user:" + username + ":" + kind
+}
+
+// itemKey is namespaced by the whiteboard the item is on too, as each whiteboard numbers its items from 1.
+func itemKey(backend string, itemId string, kind string) string {
+	if len(backend) == 0 {
+		backend = config.DEFAULT_BACKEND
+	}
+	return KEY_NAMESPACE + "item:" + backend + ":" + itemId + ":" + kind
+}
+
+func StandupKey(channel string) string {
+	return channelKey(channel, "standup")
+}
+
+// NamedStandupKey is the channel's standup registered under the alias, each kept on its own so registering one doesn't
+// touch the others.
+func NamedStandupKey(channel string, alias string) string {
+	return channelKey(channel, "standups:" + alias)
+}
+
+func EntryKey(username string) string {
+	return userKey(username, "entry")
+}
+
+func PendingDeleteKey(channel string, username string) string {
+	return channelKey(channel, "delete:" + username)
+}
+
+// EditCandidatesKey is the entries listed by the user's last `wb edit` in the channel, which `wb edit #1` picks from.
+func EditCandidatesKey(channel string, username string) string {
+	return channelKey(channel, "edit:" + username)
+}
+
+func EntryMessageKey(backend string, itemId string) string {
+	return itemKey(backend, itemId, "message")
+}
+
+func ThreadKey(channel string, timestamp string) string {
+	return channelKey(channel, "thread:" + timestamp)
+}
+
+func MessageSeenKey(channel string, timestamp string) string {
+	return channelKey(channel, "seen:" + timestamp)
+}
+
+// ScheduleKey is the schedule of the channel's standup with the alias, or of its default standup without one. They're
+// kept together outside the channels, so the scheduler finds the scheduled channels from the keys alone.
+func ScheduleKey(channel string, alias string) string {
+	return SCHEDULE_KEY_PREFIX + channel + ":" + alias
+}
+
+// scheduleKeyChannel is the channel of a ScheduleKey, as Slack's channel ids never have a colon in them.
+func scheduleKeyChannel(key string) (channel string, ok bool) {
+	channel = strings.TrimPrefix(key, SCHEDULE_KEY_PREFIX)
+	end := strings.Index(channel, ":")
+	if !strings.HasPrefix(key, SCHEDULE_KEY_PREFIX) || end <= 0 {
+		return "", false
+	}
+	return channel[:end], true
+}
+
+func SectionOrderKey(channel string) string {
+	return channelKey(channel, "order")
+}
+
+// AuthorKey is the Slack user id of whoever created the item with the bot, which is who can delete it.
+func AuthorKey(backend string, itemId string) string {
+	return itemKey(backend, itemId, "author")
+}
+
+func HistoryKey(backend string, itemId string) string {
+	return itemKey(backend, itemId, "history")
+}
+
+func BulkKey(channel string, username string) string {
+	return channelKey(channel, "bulk:" + username)
 }
 
-func (store *RealStore) SetStandup(channel string, standup Standup) {
-	standupJson, _ := json.Marshal(standup)
-	store.Set(channel, string(standupJson))
+func PostKey(channel string) string {
+	return channelKey(channel, "post")
 }
 
+func PendingEmailKey(channel string, postId string) string {
+	return channelKey(channel, "email:" + postId)
+}
+
+func PendingUnregisterKey(channel string, username string) string {
+	return channelKey(channel, "unregister:" + username)
+}
+
+// Get looks up the key, where a key that isn't there is a quiet miss, as most messages look for one that isn't.
 func (store *RealStore) Get(key string) (value string, ok bool) {
 	conn := store.Pool.Get()
 	defer conn.Close()
 
 	value, err := redis.String(conn.Do("GET", key))
 	ok = err == nil
-	if !ok {
+	if !ok && err != redis.ErrNil {
 		fmt.Printf("Error occurred GETing from Redis: %v", err)
 	}
 	return
@@ -62,4 +223,95 @@ func (store *RealStore) Set(key string, value string) {
 	if err != nil {
 		return
 	}
-}
\ No newline at end of file
+}
+
+func (store *RealStore) SetExpiring(key string, value string, expiry time.Duration) {
+	conn := store.Pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("SET", key, value, "EX", int(expiry.Seconds()))
+	if err != nil {
+		fmt.Printf("Error occurred SETing in Redis: %v", err)
+	}
+}
+
+// Keys lists the keys starting with the prefix, scanning through them rather than blocking Redis with KEYS.
+func (store *RealStore) Keys(prefix string) (keys []string) {
+	conn := store.Pool.Get()
+	defer conn.Close()
+
+	pattern := globEscaper.Replace(prefix) + "*"
+	cursor := 0
+	for {
+		values, err := redis.Values(conn.Do("SCAN", cursor, "MATCH", pattern, "COUNT", SCAN_COUNT))
+		if err != nil {
+			fmt.Printf("Error occurred SCANning Redis: %v", err)
+			return
+		}
+		var found []string
+		if _, err = redis.Scan(values, &cursor, &found); err != nil {
+			fmt.Printf("Error occurred SCANning Redis: %v", err)
+			return
+		}
+		keys = append(keys, found...)
+		if cursor == 0 {
+			return
+		}
+	}
+}
+
+// SetIfMissing sets the key unless it's already there, with SET NX so only one of several callers sets it. It expires
+// after expiry, or never when that's zero.
+func (store *RealStore) SetIfMissing(key string, value string, expiry time.Duration) (ok bool) {
+	conn := store.Pool.Get()
+	defer conn.Close()
+
+	args := []interface{}{key, value, "NX"}
+	if expiry > 0 {
+		args = append(args, "EX", int(expiry.Seconds()))
+	}
+	_, err := redis.String(conn.Do("SET", args...))
+	if err != nil && err != redis.ErrNil {
+		fmt.Printf("Error occurred SETing in Redis: %v", err)
+	}
+	return err == nil
+}
+
+// Take gets the key and deletes it in one transaction, so only one of several callers gets it.
+func (store *RealStore) Take(key string) (value string, ok bool) {
+	conn := store.Pool.Get()
+	defer conn.Close()
+
+	conn.Send("MULTI")
+	conn.Send("GET", key)
+	conn.Send("DEL", key)
+	values, err := redis.Values(conn.Do("EXEC"))
+	if err != nil {
+		fmt.Printf("Error occurred GETing from Redis: %v", err)
+		return
+	}
+	value, err = redis.String(values[0], nil)
+	return value, err == nil
+}
+
+// SchemaVersion is the version of the keys in Redis, where data from before the schema was versioned is version 1.
+func (store *RealStore) SchemaVersion() int {
+	conn := store.Pool.Get()
+	defer conn.Close()
+
+	version, err := redis.Int(conn.Do("GET", SCHEMA_VERSION_KEY))
+	if err != nil {
+		return 1
+	}
+	return version
+}
+
+func (store *RealStore) Delete(key string) {
+	conn := store.Pool.Get()
+	defer conn.Close()
+
+	_, err := conn.Do("DEL", key)
+	if err != nil {
+		fmt.Printf("Error occurred DELeting from Redis: %v", err)
+	}
+}
@@ -0,0 +1,150 @@
+package app_test
+
+import (
+	"github.com/alicebob/miniredis/v2"
+	"github.com/garyburd/redigo/redis"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+	"time"
+)
+
+var _ = Describe("RealStore", func() {
+	var (
+		server *miniredis.Miniredis
+		store  *RealStore
+	)
+
+	BeforeEach(func() {
+		var err error
+		server, err = miniredis.Run()
+		Expect(err).To(BeNil())
+		store = NewRealStore(&redis.Pool{Dial: func() (redis.Conn, error) {
+			return redis.Dial("tcp", server.Addr())
+		}})
+	})
+
+	AfterEach(func() {
+		store.Pool.Close()
+		server.Close()
+	})
+
+	It("should keep data under namespaced keys", func() {
+		store.SetStandup("C123", model.Standup{Id: 1, Title: "Sydney"})
+		store.SetEntry("aleung", model.Interesting{Entry: &model.Entry{Title: "Something interesting", ItemKind: "Interesting"}})
+
+		Expect(server.Keys()).To(Equal([]string{"wb:v3:channel:C123:standup", "wb:v3:user:aleung:entry"}))
+		Expect(server.TTL("wb:v3:user:aleung:entry")).To(Equal(ENTRY_EXPIRY))
+		standup, ok := store.GetStandup("C123")
+		Expect(ok).To(BeTrue())
+		Expect(standup.Title).To(Equal("Sydney"))
+	})
+
+	Describe("migrating", func() {
+		BeforeEach(func() {
+			server.Set("C123", `{"id":1,"title":"Sydney","time_zone_name_iana":"Australia/Sydney"}`)
+			server.Set("entry:aleung", model.MarshalEntry(model.Interesting{Entry: &model.Entry{Title: "Something interesting", ItemKind: "Interesting"}}))
+			server.SetTTL("entry:aleung", time.Hour)
+			server.Set("C999", "not a standup")
+			server.Set("entry:dlorenc", `{"kind":"Unknown","title":"not an entry"}`)
+			server.Set("lawrence", model.MarshalEntry(model.Help{Entry: &model.Entry{Title: "Not under entry:", ItemKind: "Help"}}))
+			server.Set("something:else", "left alone")
+		})
+
+		It("should start at the first version", func() {
+			Expect(store.SchemaVersion()).To(Equal(1))
+		})
+
+		It("should move the legacy keys to the current schema", func() {
+			migrated, err := store.Migrate()
+			Expect(err).To(BeNil())
+			Expect(migrated).To(Equal(2))
+			Expect(store.SchemaVersion()).To(Equal(SCHEMA_VERSION))
+			Expect(server.Keys()).To(ConsistOf(
+				"C999",
+				"entry:dlorenc",
+				"lawrence",
+				"something:else",
+				"wb:schema_version",
+				"wb:v3:channel:C123:standup",
+				"wb:v3:user:aleung:entry",
+			))
+
+			standup, ok := store.GetStandup("C123")
+			Expect(ok).To(BeTrue())
+			Expect(standup.TimeZone).To(Equal("Australia/Sydney"))
+			entryType, ok := store.GetEntry("aleung")
+			Expect(ok).To(BeTrue())
+			Expect(entryType.GetEntry().Title).To(Equal("Something interesting"))
+			Expect(server.TTL("wb:v3:user:aleung:entry")).To(Equal(time.Hour))
+		})
+
+		It("should move the entries kept under entry:<username>, but not bare usernames", func() {
+			conn := store.Pool.Get()
+			defer conn.Close()
+			conn.Do("SET", "entry:alice", model.MarshalEntry(model.Event{Entry: &model.Entry{Title: "Meetup", ItemKind: "Event"}}), "EX", int(ENTRY_EXPIRY.Seconds()))
+
+			store.Migrate()
+			entryType, ok := store.GetEntry("alice")
+			Expect(ok).To(BeTrue())
+			Expect(entryType.GetEntry().Title).To(Equal("Meetup"))
+			Expect(server.TTL(EntryKey("alice"))).To(Equal(ENTRY_EXPIRY))
+			Expect(server.Exists("entry:alice")).To(BeFalse())
+			_, ok = store.GetEntry("lawrence")
+			Expect(ok).To(BeFalse())
+		})
+
+		It("should not overwrite keys already in the current schema", func() {
+			store.SetStandup("C123", model.Standup{Id: 2, Title: "Newer"})
+			store.Migrate()
+			standup, _ := store.GetStandup("C123")
+			Expect(standup.Title).To(Equal("Newer"))
+			Expect(server.Exists("C123")).To(BeTrue())
+		})
+
+		It("should only migrate once", func() {
+			store.Migrate()
+			server.Set("entry:alice", model.MarshalEntry(model.Help{Entry: &model.Entry{Title: "Late", ItemKind: "Help"}}))
+			migrated, err := store.Migrate()
+			Expect(err).To(BeNil())
+			Expect(migrated).To(Equal(0))
+			Expect(server.Exists("entry:alice")).To(BeTrue())
+		})
+	})
+
+	Describe("migrating from schema v2", func() {
+		BeforeEach(func() {
+			server.Set(SCHEMA_VERSION_KEY, "2")
+			server.Set("wb:v2:channel:C123:standup", `{"id":1,"title":"Sydney","alias":"sydney"}`)
+			server.Set("wb:v2:channel:C123:standups", `[{"id":1,"title":"Sydney","alias":"sydney"},{"id":3,"title":"Singapore","alias":"sg"}]`)
+			server.Set("wb:v2:channel:C123:schedule", `{"days":[1],"hour":9,"minute":5}`)
+			server.Set("wb:v2:channel:C123:schedule:sg", `{"days":[5],"hour":10}`)
+			server.Set("wb:v2:schedules", "C123")
+			server.Set("wb:v2:user:aleung:entry", model.MarshalEntry(model.Interesting{Entry: &model.Entry{Title: "Something interesting", ItemKind: "Interesting"}}))
+			server.SetTTL("wb:v2:user:aleung:entry", time.Hour)
+		})
+
+		It("should split up the named standups, move the schedules out of the channels and the rest to v3", func() {
+			_, err := store.Migrate()
+			Expect(err).To(BeNil())
+			Expect(store.SchemaVersion()).To(Equal(SCHEMA_VERSION))
+			Expect(server.Keys()).To(ConsistOf(
+				"wb:schema_version",
+				"wb:v3:channel:C123:standup",
+				"wb:v3:channel:C123:standups:sg",
+				"wb:v3:channel:C123:standups:sydney",
+				"wb:v3:schedule:C123:",
+				"wb:v3:schedule:C123:sg",
+				"wb:v3:user:aleung:entry",
+			))
+
+			standups, _ := store.GetNamedStandups("C123")
+			Expect(standups).To(Equal([]model.Standup{{Id: 3, Title: "Singapore", Alias: "sg"}, {Id: 1, Title: "Sydney", Alias: "sydney"}}))
+			schedule, ok := store.GetSchedule("C123", "sg")
+			Expect(ok).To(BeTrue())
+			Expect(schedule.Hour).To(Equal(10))
+			Expect(server.TTL("wb:v3:user:aleung:entry")).To(Equal(time.Hour))
+		})
+	})
+})
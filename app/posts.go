@@ -0,0 +1,95 @@
+package app
+
+import (
+	"fmt"
+	"github.com/nlopes/slack"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+)
+
+func (whiteboard WhiteboardApp) handleArchiveCommand(title string, ev *slack.MessageEvent, context CommandContext) {
+	standup, _, _, ok := whiteboard.getEntryDetails(ev, context)
+	if !ok {
+		return
+	}
+	restClient := whiteboard.restClientFor(standup)
+	items, err := restClient.GetStandupItems(standup.Id)
+	if err != nil {
+		handleRestError(whiteboard.SlackClient, err, ev.Channel)
+		return
+	}
+	if items.Empty() {
+		handleNoEntries(whiteboard.SlackClient, ev.Channel)
+		return
+	}
+
+	if len(title) == 0 {
+		title = fmt.Sprintf("%v %v", standup.Title, whiteboard.today(standup))
+	}
+	postId, err := restClient.CreatePost(NewPostRequest(standup.Id, title))
+	if err != nil {
+		whiteboard.SlackClient.PostReply(fmt.Sprintf("I couldn't archive the standup. %v", RestErrorMessage(err)), ev.Channel, THUMBS_DOWN)
+		return
+	}
+	whiteboard.Store.SetArchivedPost(ev.Channel, ArchivedPost{PostId: postId, Standup: standup})
+	whiteboard.SlackClient.PostMessage(fmt.Sprintf("Today's entries have been archived into post %v: %v\nPreview the email with `wb email`", postId, title), ev.Channel, THUMBS_UP)
+}
+
+// handleEmailCommand previews the email for the channel's latest post, from the standup it was archived from whichever
+// standup is picked, and sends it once confirmed.
+func (whiteboard WhiteboardApp) handleEmailCommand(input string, ev *slack.MessageEvent, context CommandContext) {
+	_, slackUser, _, ok := whiteboard.getEntryDetails(ev, context)
+	if !ok {
+		return
+	}
+	if input == EMAIL_CONFIRMATION {
+		whiteboard.handleEmailConfirmation(ev)
+		return
+	}
+
+	archived, ok := whiteboard.Store.GetArchivedPost(ev.Channel)
+	if !ok || len(archived.PostId) == 0 {
+		whiteboard.SlackClient.PostReply("There's no post to email yet. Archive today's entries with `wb archive` first!", ev.Channel, THUMBS_DOWN)
+		return
+	}
+	post, err := whiteboard.restClientFor(archived.Standup).GetPost(archived.PostId)
+	if err != nil {
+		handleRestError(whiteboard.SlackClient, err, ev.Channel)
+		return
+	}
+
+	whiteboard.Store.SetExpiring(PendingEmailKey(ev.Channel, archived.PostId), slackUser.Username, CONFIRMATION_EXPIRY)
+	whiteboard.SlackClient.PostMessage(fmt.Sprintf("Here's a preview of the email:\n\n%v\n\nSend it with `wb email yes`", post), ev.Channel, "")
+}
+
+func (whiteboard WhiteboardApp) handleEmailConfirmation(ev *slack.MessageEvent) {
+	archived, ok := whiteboard.takePendingEmail(ev.Channel)
+	if !ok {
+		whiteboard.SlackClient.PostReply("There's no email waiting to be sent. Preview it with `wb email` first!", ev.Channel, THUMBS_DOWN)
+		return
+	}
+
+	if err := whiteboard.restClientFor(archived.Standup).SendPostEmail(NewSendEmailRequest(archived.PostId)); err != nil {
+		whiteboard.SlackClient.PostReply(fmt.Sprintf("I couldn't send the email for post %v. %v", archived.PostId, RestErrorMessage(err)), ev.Channel, THUMBS_DOWN)
+		return
+	}
+	whiteboard.Store.Delete(PostKey(ev.Channel))
+	whiteboard.SlackClient.PostMessage(fmt.Sprintf("The email for post %v has been sent!", archived.PostId), ev.Channel, THUMBS_UP)
+}
+
+// takePendingEmail claims the email for the channel's post, as long as it's been previewed in the last
+// CONFIRMATION_EXPIRY. Anyone in the channel can confirm it, but it has to be previewed again after each try. Taking
+// the preview from the store makes sure that when several people confirm the same email, only the first one sends it.
+func (whiteboard WhiteboardApp) takePendingEmail(channel string) (archived ArchivedPost, ok bool) {
+	archived, ok = whiteboard.Store.GetArchivedPost(channel)
+	if !ok || len(archived.PostId) == 0 {
+		return ArchivedPost{}, false
+	}
+	if _, ok = whiteboard.Store.Take(PendingEmailKey(channel, archived.PostId)); !ok {
+		return ArchivedPost{}, false
+	}
+	return archived, true
+}
+
+func (whiteboard WhiteboardApp) today(standup Standup) string {
+	return whiteboard.Clock.Now().In(standup.Location()).Format(DATE_FORMAT)
+}
@@ -4,89 +4,338 @@ import (
 	"encoding/json"
 	"bytes"
 	"fmt"
-	"os"
-	"errors"
+	"context"
+	"time"
 	"strings"
 	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"github.com/pivotal-sydney/whiteboardbot/config"
 	"io/ioutil"
+	"regexp"
+	"errors"
+)
+
+const (
+	REQUEST_TIMEOUT = 10 * time.Second
+	MAX_ATTEMPTS = 3
+	RETRY_BACKOFF = 500 * time.Millisecond
 )
 
 type RestClient interface {
-	Post(request WhiteboardRequest) (itemId string, ok bool)
-	GetStandupItems(standupId int) (items StandupItems, ok bool)
-	GetStandup(standupId string) (standup Standup, ok bool)
+	ForBackend(name string) (client RestClient, ok bool)
+	Post(request WhiteboardRequest) (itemId string, err error)
+	Delete(request WhiteboardRequest) (err error)
+	GetStandupItems(standupId int) (items StandupItems, err error)
+	GetStandup(standupId string) (standup Standup, err error)
+	CreatePost(request PostRequest) (postId string, err error)
+	GetPost(postId string) (post Post, err error)
+	SendPostEmail(request PostRequest) (err error)
+	HostUrl() string
 }
 
-type RealRestClient struct{}
+// formRequest is a Rails form the Whiteboard only accepts with the session's CSRF token.
+type formRequest interface {
+	SetToken(token string)
+}
+
+var postLocationPattern = regexp.MustCompile(`/posts/(\d+)`)
+
+type RealRestClient struct {
+	Config       config.Config
+	Backend      config.Backend
+	Session      *WhiteboardSession
+	Timeout      time.Duration
+	RetryBackoff time.Duration
+	sessions     map[string]*WhiteboardSession
+}
+
+type whiteboardResponse struct {
+	statusCode int
+	header     http.Header
+	body       []byte
+}
+
+func NewRestClient(config config.Config) RealRestClient {
+	client := RealRestClient{Config: config, sessions: make(map[string]*WhiteboardSession)}
+	for name, backend := range config.Backends {
+		client.sessions[name] = NewWhiteboardSession(backend.HostUrl, backend.AuthToken)
+	}
+	backend, _ := config.GetBackend("")
+	client.Backend = backend
+	client.Session = client.sessions[backend.Name]
+	return client
+}
 
-func (RealRestClient) Post(request WhiteboardRequest) (itemId string, ok bool) {
-	json, _ := json.Marshal(request)
-	fmt.Printf("Posting entry to whiteboard:\n%v\n", string(json))
-	http.DefaultClient.CheckRedirect = noRedirect
-	url := os.Getenv("WB_HOST_URL")
+// ForBackend returns a client for the named whiteboard backend, or for the default backend when there's no such backend.
+func (client RealRestClient) ForBackend(name string) (RestClient, bool) {
+	backend, ok := client.Config.GetBackend(name)
+	if !ok {
+		backend, _ = client.Config.GetBackend("")
+	}
+	client.Backend = backend
+	client.Session = client.sessions[backend.Name]
+	return client, ok
+}
+
+// unknownBackendClient stands in for a backend that's no longer in the config, so nothing meant for it is written to
+// another whiteboard.
+type unknownBackendClient struct {
+	RestClient
+	name string
+}
+
+func (client unknownBackendClient) err() error {
+	return &RestError{Kind: UNKNOWN_BACKEND_ERROR, Backend: client.name}
+}
+
+func (client unknownBackendClient) Post(request WhiteboardRequest) (string, error) {
+	return "", client.err()
+}
+
+func (client unknownBackendClient) Delete(request WhiteboardRequest) error {
+	return client.err()
+}
+
+func (client unknownBackendClient) GetStandupItems(standupId int) (StandupItems, error) {
+	return StandupItems{}, client.err()
+}
+
+func (client unknownBackendClient) GetStandup(standupId string) (Standup, error) {
+	return Standup{}, client.err()
+}
+
+func (client unknownBackendClient) CreatePost(request PostRequest) (string, error) {
+	return "", client.err()
+}
+
+func (client unknownBackendClient) GetPost(postId string) (Post, error) {
+	return Post{}, client.err()
+}
+
+func (client unknownBackendClient) SendPostEmail(request PostRequest) error {
+	return client.err()
+}
+
+func (client RealRestClient) Post(request WhiteboardRequest) (itemId string, err error) {
+	fmt.Printf("Posting entry to whiteboard %v:\n%+v\n", client.Backend.Name, request)
+	url := client.Backend.HostUrl
 	if len(request.Id) > 0 {
 		url += "/items/" + request.Id
 	} else {
 		url += fmt.Sprintf("/standups/%v/items", request.Item.StandupId)
 	}
-	httpRequest, err := http.NewRequest(toHttpVerb(request.Method), url, bytes.NewReader(json))
-	httpRequest.Header.Add("Content-Type", "application/json")
-	resp, err := http.DefaultClient.Do(httpRequest)
-	fmt.Printf("Whiteboard Request: %v\n\n", httpRequest)
-	fmt.Printf("Whiteboard Response: %v, Err: %v\n, Url: %v\n\n", resp, err, url)
 
-	ok = resp != nil && resp.StatusCode == http.StatusFound
-	defer resp.Body.Close()
-	if ok {
-		itemId = resp.Header.Get("Item-Id")
+	// Creating an item isn't idempotent, so only updates are retried.
+	method := toHttpVerb(request.Method)
+	attempts := MAX_ATTEMPTS
+	if method == "POST" {
+		attempts = 1
+	}
+	response, err := client.doWithSession(method, url, &request, attempts)
+	if err != nil {
+		return
+	}
+
+	switch response.statusCode {
+	case http.StatusFound:
+		itemId = response.header.Get("Item-Id")
+		if len(itemId) == 0 && len(request.Id) == 0 {
+			// Without the id the entry can't be updated or deleted later, so the create can't count as done.
+			err = &RestError{Kind: UNEXPECTED_STATUS_ERROR, StatusCode: response.statusCode, Err: errors.New("no Item-Id header")}
+		} else if len(itemId) == 0 {
+			itemId = request.Id
+		}
+	case http.StatusOK:
+		// The Whiteboard renders the form again instead of redirecting when the item doesn't validate.
+		err = &RestError{Kind: VALIDATION_ERROR, StatusCode: response.statusCode}
+	default:
+		err = NewStatusError(response.statusCode)
+	}
+	return
+}
+
+func (client RealRestClient) Delete(request WhiteboardRequest) (err error) {
+	fmt.Printf("Deleting entry from whiteboard %v:\n%+v\n", client.Backend.Name, request)
+	url := client.Backend.HostUrl + "/items/" + request.Id
+	response, err := client.doWithSession(toHttpVerb(request.Method), url, &request, MAX_ATTEMPTS)
+	if err != nil {
+		return
 	}
-	if (len(itemId) == 0) {
-		itemId = request.Id
+
+	switch response.statusCode {
+	case http.StatusFound, http.StatusOK, http.StatusNoContent:
+	default:
+		err = NewStatusError(response.statusCode)
 	}
 	return
 }
 
-func (RealRestClient) GetStandupItems(standupId int) (items StandupItems, ok bool) {
-	url := fmt.Sprintf("%v/standups/%v/items", os.Getenv("WB_HOST_URL"), standupId)
-	httpRequest, _ := http.NewRequest("GET", url, nil)
-	httpRequest.Header.Add("Accept", "application/json")
-	resp, err := http.DefaultClient.Do(httpRequest)
-	defer resp.Body.Close()
-	ok = err == nil && resp != nil && resp.StatusCode == http.StatusOK
-
-	if ok {
-		jsonBlob, err := ioutil.ReadAll(resp.Body)
-		ok = err == nil
-		if ok {
-			err = json.Unmarshal(jsonBlob, &items)
-			ok = err == nil
+func (client RealRestClient) HostUrl() string {
+	return client.Backend.HostUrl
+}
+
+func (client RealRestClient) GetStandupItems(standupId int) (items StandupItems, err error) {
+	err = client.getJson(fmt.Sprintf("%v/standups/%v/items", client.Backend.HostUrl, standupId), &items)
+	return
+}
+
+func (client RealRestClient) GetStandup(standupId string) (standup Standup, err error) {
+	err = client.getJson(fmt.Sprintf("%v/standups/%v", client.Backend.HostUrl, standupId), &standup)
+	return
+}
+
+func (client RealRestClient) CreatePost(request PostRequest) (postId string, err error) {
+	fmt.Printf("Creating post on whiteboard %v:\n%+v\n", client.Backend.Name, request)
+	url := fmt.Sprintf("%v/standups/%v/posts", client.Backend.HostUrl, request.Post.StandupId)
+	response, err := client.doWithSession("POST", url, &request, 1)
+	if err != nil {
+		return
+	}
+
+	switch response.statusCode {
+	case http.StatusFound:
+		postId = response.header.Get("Post-Id")
+		if match := postLocationPattern.FindStringSubmatch(response.header.Get("Location")); len(postId) == 0 && match != nil {
+			postId = match[1]
 		}
+	case http.StatusOK:
+		err = &RestError{Kind: VALIDATION_ERROR, StatusCode: response.statusCode}
+	default:
+		err = NewStatusError(response.statusCode)
 	}
 	return
 }
 
-func (RealRestClient) GetStandup(standupId string) (standup Standup, ok bool) {
-	url := fmt.Sprintf("%v/standups/%v", os.Getenv("WB_HOST_URL"), standupId)
-	httpRequest, _ := http.NewRequest("GET", url, nil)
-	httpRequest.Header.Add("Accept", "application/json")
-	resp, err := http.DefaultClient.Do(httpRequest)
-	defer resp.Body.Close()
-	ok = err == nil && resp != nil && resp.StatusCode == http.StatusOK
-
-	if ok {
-		jsonBlob, err := ioutil.ReadAll(resp.Body)
-		ok = err == nil
-		if ok {
-			err = json.Unmarshal(jsonBlob, &standup)
-			ok = err == nil
+func (client RealRestClient) GetPost(postId string) (post Post, err error) {
+	err = client.getJson(fmt.Sprintf("%v/posts/%v", client.Backend.HostUrl, postId), &post)
+	return
+}
+
+func (client RealRestClient) SendPostEmail(request PostRequest) (err error) {
+	fmt.Printf("Sending email for post %v on whiteboard %v\n", request.Id, client.Backend.Name)
+	url := fmt.Sprintf("%v/posts/%v/send_email", client.Backend.HostUrl, request.Id)
+	// A retry could send the email twice, so this is only tried once.
+	response, err := client.doWithSession(toHttpVerb(request.Method), url, &request, 1)
+	if err != nil {
+		return
+	}
+
+	switch response.statusCode {
+	case http.StatusFound, http.StatusOK, http.StatusNoContent:
+	default:
+		err = NewStatusError(response.statusCode)
+	}
+	return
+}
+
+func (client RealRestClient) getJson(url string, value interface{}) (err error) {
+	response, err := client.doWithSession("GET", url, nil, MAX_ATTEMPTS)
+	if err != nil {
+		return
+	}
+	if response.statusCode != http.StatusOK {
+		return NewStatusError(response.statusCode)
+	}
+	if err = json.Unmarshal(response.body, value); err != nil {
+		return &RestError{Kind: UNEXPECTED_STATUS_ERROR, StatusCode: response.statusCode, Err: err}
+	}
+	return
+}
+
+// doWithSession sends the request with the session's CSRF token, starting a new session and trying once more when
+// the Whiteboard rejects the old one.
+func (client RealRestClient) doWithSession(method string, url string, request formRequest, attempts int) (response whiteboardResponse, err error) {
+	session := client.Session
+	if session == nil {
+		session = NewWhiteboardSession(client.Backend.HostUrl, client.Backend.AuthToken)
+	}
+
+	for refreshed := false; ; refreshed = true {
+		var body []byte
+		if request != nil {
+			var token string
+			if token, err = session.Token(client.timeout()); err != nil {
+				return
+			}
+			request.SetToken(token)
+			body, _ = json.Marshal(request)
 		}
+		response, err = client.do(session, method, url, body, attempts)
+		if err != nil || !sessionExpired(response) {
+			return
+		}
+		if refreshed {
+			// A redirect to the login page isn't an error by its status, so it's reported here rather than taken
+			// for success by the caller.
+			return response, &RestError{Kind: AUTH_ERROR, StatusCode: response.statusCode}
+		}
+		fmt.Printf("Whiteboard session expired, logging in again\n")
+		if _, err = session.Refresh(client.timeout()); err != nil {
+			return
+		}
+	}
+}
+
+// do sends the request, retrying network failures and server errors with an increasing backoff.
+func (client RealRestClient) do(session *WhiteboardSession, method string, url string, body []byte, attempts int) (response whiteboardResponse, err error) {
+	backoff := client.RetryBackoff
+	if backoff == 0 {
+		backoff = RETRY_BACKOFF
+	}
+	for attempt := 1; ; attempt++ {
+		response, err = client.send(session, method, url, body)
+		fmt.Printf("Whiteboard Response: %v, Err: %v\n, Url: %v\n\n", response.statusCode, err, url)
+		temporary := err != nil || response.statusCode >= http.StatusInternalServerError
+		if !temporary || attempt >= attempts {
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+	}
+}
+
+func (client RealRestClient) send(session *WhiteboardSession, method string, url string, body []byte) (response whiteboardResponse, err error) {
+	ctx, cancel := context.WithTimeout(context.Background(), client.timeout())
+	defer cancel()
+
+	var httpRequest *http.Request
+	if body != nil {
+		httpRequest, err = http.NewRequest(method, url, bytes.NewReader(body))
+	} else {
+		httpRequest, err = http.NewRequest(method, url, nil)
+	}
+	if err != nil {
+		return response, &RestError{Kind: NETWORK_ERROR, Err: err}
+	}
+	if body != nil {
+		httpRequest.Header.Add("Content-Type", "application/json")
+	} else {
+		httpRequest.Header.Add("Accept", "application/json")
+	}
+
+	resp, err := session.httpClient.Do(httpRequest.WithContext(ctx))
+	if err != nil {
+		return response, &RestError{Kind: NETWORK_ERROR, Err: err}
+	}
+	defer resp.Body.Close()
+
+	response.statusCode = resp.StatusCode
+	response.header = resp.Header
+	if response.body, err = ioutil.ReadAll(resp.Body); err != nil {
+		return response, &RestError{Kind: NETWORK_ERROR, Err: err}
 	}
 	return
 }
 
-func PostEntryToWhiteboard(restClient RestClient, entryType EntryType) (itemId string, ok bool) {
+func (client RealRestClient) timeout() time.Duration {
+	if client.Timeout == 0 {
+		return REQUEST_TIMEOUT
+	}
+	return client.Timeout
+}
+
+func PostEntryToWhiteboard(restClient RestClient, entryType EntryType) (itemId string, err error) {
 	var request = createRequest(entryType, entryType.GetEntry() != nil && len(entryType.GetEntry().Id) > 0)
-	itemId, ok = restClient.Post(request)
+	itemId, err = restClient.Post(request)
 	return
 }
 
@@ -99,7 +348,7 @@ func createRequest(entryType EntryType, existingEntry bool) WhiteboardRequest {
 }
 
 func noRedirect(req *http.Request, via []*http.Request) error {
-	return errors.New("Don't redirect!")
+	return http.ErrUseLastResponse
 }
 
 func toHttpVerb(method string) string {
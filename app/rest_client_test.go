@@ -0,0 +1,188 @@
+package app_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/config"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"time"
+)
+
+var _ = Describe("Rest Client", func() {
+
+	var (
+		client    RealRestClient
+		server    *httptest.Server
+		requests  int
+		responses []int
+		request   model.WhiteboardRequest
+		location  string
+		itemId    string
+		method    string
+		path      string
+	)
+
+	BeforeEach(func() {
+		requests = 0
+		responses = nil
+		location = "/standups/1"
+		itemId = "42"
+		client = RealRestClient{Timeout: time.Second, RetryBackoff: time.Millisecond}
+		server = httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, req *http.Request) {
+			status := responses[requests]
+			requests++
+			method, path = req.Method, req.URL.Path
+			json.NewDecoder(req.Body).Decode(&request)
+			if status == http.StatusFound {
+				if len(itemId) > 0 {
+					responseWriter.Header().Set("Item-Id", itemId)
+				}
+				responseWriter.Header().Set("Location", location)
+			}
+			responseWriter.WriteHeader(status)
+			if status == http.StatusOK {
+				responseWriter.Write([]byte(`{"id": 1, "title": "Sydney"}`))
+			}
+		}))
+		client.Backend = config.Backend{HostUrl: server.URL, AuthToken: "token"}
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	newItem := model.WhiteboardRequest{Item: model.Item{StandupId: 1, Title: "Something"}}
+
+	Context("posting an item", func() {
+		It("should return the id of the created item", func() {
+			responses = []int{http.StatusFound}
+			itemId, err := client.Post(newItem)
+			Expect(err).To(BeNil())
+			Expect(itemId).To(Equal("42"))
+			Expect(request.Token).To(Equal("token"))
+		})
+
+		It("should fail creating an item when the whiteboard doesn't say its id", func() {
+			responses = []int{http.StatusFound}
+			itemId = ""
+			_, err := client.Post(newItem)
+			Expect(err.(*RestError).Kind).To(Equal(UNEXPECTED_STATUS_ERROR))
+		})
+
+		It("should keep the id of an updated item when the whiteboard doesn't say it", func() {
+			responses = []int{http.StatusFound}
+			itemId = ""
+			updatedId, err := client.Post(model.WhiteboardRequest{Method: "patch", Id: "42"})
+			Expect(err).To(BeNil())
+			Expect(updatedId).To(Equal("42"))
+		})
+
+		It("should report a validation error when the form is rendered again", func() {
+			responses = []int{http.StatusOK}
+			_, err := client.Post(newItem)
+			Expect(err.(*RestError).Kind).To(Equal(VALIDATION_ERROR))
+		})
+
+		It("should not retry creating an item", func() {
+			responses = []int{http.StatusServiceUnavailable, http.StatusFound}
+			_, err := client.Post(newItem)
+			Expect(err.(*RestError).Kind).To(Equal(UNEXPECTED_STATUS_ERROR))
+			Expect(requests).To(Equal(1))
+		})
+
+		It("should retry updating an item", func() {
+			responses = []int{http.StatusServiceUnavailable, http.StatusFound}
+			itemId, err := client.Post(model.WhiteboardRequest{Method: "patch", Id: "42"})
+			Expect(err).To(BeNil())
+			Expect(itemId).To(Equal("42"))
+			Expect(requests).To(Equal(2))
+		})
+	})
+
+	Context("archiving a standup", func() {
+		It("should return the id of the created post", func() {
+			responses = []int{http.StatusFound}
+			location = "/posts/7/edit"
+			postId, err := client.CreatePost(model.NewPostRequest(1, "Sydney 2015-01-02"))
+			Expect(err).To(BeNil())
+			Expect(postId).To(Equal("7"))
+			Expect(path).To(Equal("/standups/1/posts"))
+		})
+
+		It("should get a post", func() {
+			responses = []int{http.StatusOK}
+			post, err := client.GetPost("1")
+			Expect(err).To(BeNil())
+			Expect(post.Id).To(Equal("1"))
+			Expect(post.Title).To(Equal("Sydney"))
+		})
+
+		It("should not retry sending an email", func() {
+			responses = []int{http.StatusServiceUnavailable, http.StatusFound}
+			err := client.SendPostEmail(model.NewSendEmailRequest("7"))
+			Expect(err.(*RestError).Kind).To(Equal(UNEXPECTED_STATUS_ERROR))
+			Expect(requests).To(Equal(1))
+			Expect(method).To(Equal("PUT"))
+			Expect(path).To(Equal("/posts/7/send_email"))
+		})
+	})
+
+	Context("choosing a backend", func() {
+		BeforeEach(func() {
+			client.Config = config.Config{DefaultBackend: "sydney", Backends: map[string]config.Backend{
+				"sydney":    config.Backend{Name: "sydney", HostUrl: "http://sydney.example.com"},
+				"singapore": config.Backend{Name: "singapore", HostUrl: server.URL, AuthToken: "singapore-token"},
+			}}
+		})
+
+		It("should send requests to the named backend", func() {
+			responses = []int{http.StatusFound}
+			singapore, ok := client.ForBackend("singapore")
+			Expect(ok).To(BeTrue())
+			_, err := singapore.Post(newItem)
+			Expect(err).To(BeNil())
+			Expect(request.Token).To(Equal("singapore-token"))
+		})
+
+		It("should fall back to the default backend", func() {
+			melbourne, ok := client.ForBackend("melbourne")
+			Expect(ok).To(BeFalse())
+			Expect(melbourne.(RealRestClient).Backend.Name).To(Equal("sydney"))
+		})
+	})
+
+	Context("getting a standup", func() {
+		It("should retry until it succeeds", func() {
+			responses = []int{http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusOK}
+			standup, err := client.GetStandup("1")
+			Expect(err).To(BeNil())
+			Expect(standup.Title).To(Equal("Sydney"))
+			Expect(requests).To(Equal(3))
+		})
+
+		It("should give up after a few attempts", func() {
+			responses = []int{http.StatusBadGateway, http.StatusBadGateway, http.StatusBadGateway}
+			_, err := client.GetStandup("1")
+			Expect(err.(*RestError).StatusCode).To(Equal(http.StatusBadGateway))
+			Expect(requests).To(Equal(MAX_ATTEMPTS))
+		})
+
+		It("should report a missing standup", func() {
+			responses = []int{http.StatusNotFound}
+			_, err := client.GetStandup("1")
+			Expect(IsNotFound(err)).To(BeTrue())
+			Expect(requests).To(Equal(1))
+		})
+
+		It("should report a network error without panicking", func() {
+			server.Close()
+			_, err := client.GetStandup("1")
+			Expect(err.(*RestError).Kind).To(Equal(NETWORK_ERROR))
+			Expect(RestErrorMessage(err)).To(Equal("I couldn't reach the Whiteboard, try again in a bit."))
+		})
+	})
+})
@@ -0,0 +1,86 @@
+package app
+
+import (
+	"fmt"
+	"net/http"
+)
+
+type RestErrorKind int
+
+const (
+	NETWORK_ERROR RestErrorKind = iota
+	AUTH_ERROR
+	NOT_FOUND_ERROR
+	VALIDATION_ERROR
+	UNEXPECTED_STATUS_ERROR
+	UNKNOWN_BACKEND_ERROR
+)
+
+type RestError struct {
+	Kind       RestErrorKind
+	StatusCode int
+	Backend    string
+	Err        error
+}
+
+func NewStatusError(statusCode int) *RestError {
+	switch statusCode {
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusUnprocessableEntity:
+		return &RestError{Kind: AUTH_ERROR, StatusCode: statusCode}
+	case http.StatusNotFound:
+		return &RestError{Kind: NOT_FOUND_ERROR, StatusCode: statusCode}
+	case http.StatusBadRequest:
+		return &RestError{Kind: VALIDATION_ERROR, StatusCode: statusCode}
+	default:
+		return &RestError{Kind: UNEXPECTED_STATUS_ERROR, StatusCode: statusCode}
+	}
+}
+
+func (err *RestError) Error() string {
+	switch err.Kind {
+	case NETWORK_ERROR:
+		return fmt.Sprintf("whiteboard network error: %v", err.Err)
+	case AUTH_ERROR:
+		return fmt.Sprintf("whiteboard rejected the auth token with status %v", err.StatusCode)
+	case NOT_FOUND_ERROR:
+		return "whiteboard could not find the resource"
+	case VALIDATION_ERROR:
+		return fmt.Sprintf("whiteboard rejected the entry with status %v", err.StatusCode)
+	case UNKNOWN_BACKEND_ERROR:
+		return fmt.Sprintf("unknown whiteboard %v", err.Backend)
+	default:
+		if err.Err != nil {
+			return fmt.Sprintf("whiteboard responded with status %v and an unreadable body: %v", err.StatusCode, err.Err)
+		}
+		return fmt.Sprintf("whiteboard responded with unexpected status %v", err.StatusCode)
+	}
+}
+
+func RestErrorMessage(err error) string {
+	restError, ok := err.(*RestError)
+	if !ok {
+		return fmt.Sprintf("Something went wrong talking to the Whiteboard: %v", err)
+	}
+	switch restError.Kind {
+	case NETWORK_ERROR:
+		return "I couldn't reach the Whiteboard, try again in a bit."
+	case AUTH_ERROR:
+		return "The Whiteboard rejected my session, even after logging in again."
+	case NOT_FOUND_ERROR:
+		return "The Whiteboard couldn't find that."
+	case VALIDATION_ERROR:
+		return "The Whiteboard didn't accept the entry, check its details."
+	case UNKNOWN_BACKEND_ERROR:
+		return fmt.Sprintf("I don't know a whiteboard called: %v. Register the standup again with `wb r`.", restError.Backend)
+	default:
+		if restError.Err != nil {
+			return "The Whiteboard sent back something I couldn't read."
+		}
+		return fmt.Sprintf("The Whiteboard responded with an unexpected status: %v", restError.StatusCode)
+	}
+}
+
+func IsNotFound(err error) bool {
+	restError, ok := err.(*RestError)
+	return ok && restError.Kind == NOT_FOUND_ERROR
+}
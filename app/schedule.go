@@ -0,0 +1,97 @@
+package app
+
+import (
+	"fmt"
+	"github.com/nlopes/slack"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"sort"
+	"strings"
+)
+
+const (
+	SCHEDULE_OFF = "off"
+)
+
+// ScheduledStandup is one of a channel's standups along with its schedule.
+type ScheduledStandup struct {
+	Standup  Standup
+	Schedule Schedule
+}
+
+// handleScheduleCommand shows, sets or turns off the schedule of the standup picked with `wb @name schedule`, or of
+// the channel's default standup. Each of the channel's standups has its own.
+func (whiteboard WhiteboardApp) handleScheduleCommand(input string, ev *slack.MessageEvent, context CommandContext) {
+	standup, _, _, ok := whiteboard.getEntryDetails(ev, context)
+	if !ok {
+		return
+	}
+
+	switch input {
+	case "":
+		if schedule, ok := whiteboard.Store.GetSchedule(ev.Channel, standup.Alias); ok {
+			whiteboard.SlackClient.PostReply(fmt.Sprintf("Standup %v is presented %v.", standup.Title, schedule), ev.Channel, "")
+		} else {
+			subject := "this channel"
+			if len(context.StandupName) > 0 {
+				subject = "standup " + standup.Title
+			}
+			whiteboard.SlackClient.PostReply(fmt.Sprintf("There's no schedule for %v yet. Set one like this: `wb %vschedule weekdays 09:05`", subject, standupSelector(context)), ev.Channel, "")
+		}
+	case SCHEDULE_OFF:
+		whiteboard.Store.Delete(ScheduleKey(ev.Channel, standup.Alias))
+		whiteboard.SlackClient.PostMessage(fmt.Sprintf("Standup %v won't be presented automatically any more.", standup.Title), ev.Channel, THUMBS_UP)
+	default:
+		schedule, err := ParseSchedule(input)
+		if err != nil {
+			whiteboard.SlackClient.PostReply(fmt.Sprintf("%v\nLike this: `wb %vschedule weekdays 09:05 remind 10`", err, standupSelector(context)), ev.Channel, THUMBS_DOWN)
+			return
+		}
+		whiteboard.Store.SetSchedule(ev.Channel, standup.Alias, schedule)
+		whiteboard.SlackClient.PostMessage(fmt.Sprintf("Standup %v will be presented %v (%v time).", standup.Title, schedule, standup.TimeZone), ev.Channel, THUMBS_UP)
+	}
+}
+
+// standupSelector is the `@name ` to repeat in suggested commands for the standup picked with `wb @name`.
+func standupSelector(context CommandContext) string {
+	if len(context.StandupName) == 0 {
+		return ""
+	}
+	return STANDUP_SELECTOR + strings.ToLower(context.StandupName) + " "
+}
+
+// PresentStandup posts one of the channel's standups, as `wb present` does.
+func (whiteboard WhiteboardApp) PresentStandup(channel string, standup Standup) {
+	whiteboard.presentStandup(standup, "", channel)
+}
+
+// RemindStandup asks the channel to add entries to one of its standups, naming it when it isn't the default.
+func (whiteboard WhiteboardApp) RemindStandup(channel string, standup Standup, minutes int) {
+	if defaultStandup, ok := whiteboard.Store.GetStandup(channel); ok && defaultStandup.Alias != standup.Alias {
+		whiteboard.SlackClient.PostMessage(fmt.Sprintf("Standup %v starts in %v minutes! Add your entries with `wb %v%v [face interesting help event] [title]` before then.", standup.Title, minutes, STANDUP_SELECTOR, standup.Alias), channel, "")
+		return
+	}
+	whiteboard.SlackClient.PostMessage(fmt.Sprintf("Standup starts in %v minutes! Add your entries with `wb [face interesting help event] [title]` before then.", minutes), channel, "")
+}
+
+// ScheduledStandups lists the channel's standups that have a schedule.
+func (whiteboard WhiteboardApp) ScheduledStandups(channel string) (scheduled []ScheduledStandup) {
+	for _, standup := range whiteboard.channelStandups(channel) {
+		if schedule, ok := whiteboard.Store.GetSchedule(channel, standup.Alias); ok {
+			scheduled = append(scheduled, ScheduledStandup{Standup: standup, Schedule: schedule})
+		}
+	}
+	return
+}
+
+// ScheduledChannels lists the channels with at least one standup that has a schedule, going by the schedules' keys.
+func (whiteboard WhiteboardApp) ScheduledChannels() (channels []string) {
+	scheduled := map[string]bool{}
+	for _, key := range whiteboard.Store.Keys(SCHEDULE_KEY_PREFIX) {
+		if channel, ok := scheduleKeyChannel(key); ok && !scheduled[channel] {
+			scheduled[channel] = true
+			channels = append(channels, channel)
+		}
+	}
+	sort.Strings(channels)
+	return
+}
@@ -3,22 +3,45 @@ import (
 	"fmt"
 	"github.com/nlopes/slack"
 	"github.com/pivotal-sydney/whiteboardbot/model"
+	"net/http"
+	"net/url"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	SLACK_API_URL     = "https://slack.com/api/"
+	SLACK_API_TIMEOUT = 5 * time.Second
 )
 
 type Slack struct {
 	SlackRtm *slack.RTM
+	Token    string
+}
+
+type slackApiResponse struct {
+	Ok        bool   `json:"ok"`
+	Error     string `json:"error"`
+	Timestamp string `json:"ts"`
 }
 
 type SlackUser struct {
 	Username string
 	Author string
 	TimeZone string
+	// IsAdmin is set for the workspace's admins and owners.
+	IsAdmin bool
 }
 
 type SlackClient interface {
 	PostMessage(message string, channel string, status string)
 	PostMessageWithMarkdown(message string, channel string, status string)
-	PostEntry(entry *model.Entry, channel string, status string)
+	PostEntry(entry *model.Entry, channel string, status string) (timestamp string)
+	UpdateEntry(entry *model.Entry, channel string, timestamp string, status string)
+	UpdateMessage(message string, channel string, timestamp string)
+	OpenView(triggerId string, view interface{}) (ok bool)
+	PostReply(message string, channel string, status string)
 	GetUserDetails(user string) (slackUser SlackUser)
 	GetChannelDetails(channel string) (slackChannel *slack.Channel)
 }
@@ -31,16 +54,68 @@ func (slackClient *Slack) PostMessageWithMarkdown(message string, channel string
 	slackClient.postMessage(message, channel, status, slack.PostMessageParameters{Markdown: true})
 }
 
-func (slackClient *Slack) PostEntry(entry *model.Entry, channel string, status string) {
-	message := entry.String()
-	slackClient.PostMessage(message, channel, status)
+func (slackClient *Slack) PostEntry(entry *model.Entry, channel string, status string) (timestamp string) {
+	attachments, _ := json.Marshal(NewEntryCard(entry))
+	fmt.Printf("Posting entry to slack:\n%v\n", entry.String())
+	timestamp, err := slackClient.callApi("chat.postMessage", url.Values{"channel": {channel}, "text": {status}, "attachments": {string(attachments)}, "as_user": {"true"}})
+	if err != nil {
+		fmt.Printf("Posting entry failed: %v\n", err)
+	}
+	return
+}
+
+func (slackClient *Slack) UpdateEntry(entry *model.Entry, channel string, timestamp string, status string) {
+	attachments, _ := json.Marshal(NewEntryCard(entry))
+	fmt.Printf("Updating entry in slack:\n%v\n", entry.String())
+	if _, err := slackClient.callApi("chat.update", url.Values{"channel": {channel}, "ts": {timestamp}, "text": {status}, "attachments": {string(attachments)}}); err != nil {
+		fmt.Printf("Updating entry failed: %v\n", err)
+	}
+}
+
+func (slackClient *Slack) UpdateMessage(message string, channel string, timestamp string) {
+	if _, err := slackClient.callApi("chat.update", url.Values{"channel": {channel}, "ts": {timestamp}, "text": {message}, "attachments": {"[]"}}); err != nil {
+		fmt.Printf("Updating message failed: %v\n", err)
+	}
+}
+
+func (slackClient *Slack) OpenView(triggerId string, view interface{}) (ok bool) {
+	viewJson, _ := json.Marshal(view)
+	_, err := slackClient.callApi("views.open", url.Values{"trigger_id": {triggerId}, "view": {string(viewJson)}})
+	if err != nil {
+		fmt.Printf("Opening view failed: %v\n", err)
+	}
+	return err == nil
+}
+
+func (slackClient *Slack) callApi(method string, values url.Values) (timestamp string, err error) {
+	values.Set("token", slackClient.Token)
+	httpClient := &http.Client{Timeout: SLACK_API_TIMEOUT}
+	resp, err := httpClient.PostForm(SLACK_API_URL + method, values)
+	if err != nil {
+		return
+	}
+	defer resp.Body.Close()
+
+	var response slackApiResponse
+	if err = json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return
+	}
+	if !response.Ok {
+		err = errors.New(response.Error)
+	}
+	timestamp = response.Timestamp
+	return
+}
+
+func (slackClient *Slack) PostReply(message string, channel string, status string) {
+	slackClient.PostMessageWithMarkdown(message, channel, status)
 }
 
 func (slackClient *Slack) postMessage(message string, channel string, status string, params slack.PostMessageParameters) {
 	message = status + message
 	fmt.Printf("Posting message to slack:\n%v\n", message)
 	params.AsUser = true
-	slackClient.SlackRtm.PostMessage(channel, message, params)
+	slackClient.SlackRtm.PostMessage(channel, slack.MsgOptionText(message, false), slack.MsgOptionPostMessageParameters(params))
 }
 
 func (slackClient *Slack) GetUserDetails(user string) (slackUser SlackUser) {
@@ -48,6 +123,7 @@ func (slackClient *Slack) GetUserDetails(user string) (slackUser SlackUser) {
 		slackUser.Username = userInfo.Name
 		slackUser.Author = GetAuthor(userInfo)
 		slackUser.TimeZone = userInfo.TZ
+		slackUser.IsAdmin = userInfo.IsAdmin || userInfo.IsOwner
 	} else {
 		slackUser.Username = user
 		slackUser.Author = user
@@ -76,15 +152,35 @@ func (slackClient *Slack) GetChannelDetails(channel string) *slack.Channel {
 }
 
 func handleMissingEntry(slackClient SlackClient, channel string) {
-	slackClient.PostMessageWithMarkdown("Hey, you forgot to start new entry. Start with one of `wb [face interesting help event] [title]` first!", channel, THUMBS_DOWN)
+	slackClient.PostReply("Hey, you forgot to start new entry. Start with one of `wb [face interesting help event] [title]` first!", channel, THUMBS_DOWN)
 }
 
 func handleNotRegistered(slackClient SlackClient, channel string) {
-	slackClient.PostMessage("You haven't registered your standup yet. wb r <id> first!", channel, THUMBS_DOWN)
+	slackClient.PostReply("You haven't registered your standup yet. wb r <id> first!", channel, THUMBS_DOWN)
 	return
 }
 
+func handleUnknownStandup(slackClient SlackClient, name string, channel string) {
+	slackClient.PostReply(fmt.Sprintf("There's no standup called %v in this channel. See the ones there are with `wb standups`", name), channel, THUMBS_DOWN)
+}
+
 func handleStandupNotFound(slackClient SlackClient, standupId string, channel string) {
-	slackClient.PostMessage(fmt.Sprintf("I couldn't find a standup with id: %v", standupId), channel, THUMBS_DOWN)
+	slackClient.PostReply(fmt.Sprintf("I couldn't find a standup with id: %v", standupId), channel, THUMBS_DOWN)
 	return
-}
\ No newline at end of file
+}
+
+func handleNoEntries(slackClient SlackClient, channel string) {
+	slackClient.PostReply("Hey, there's no entries in today's standup yet, why not add some?", channel, THUMBS_DOWN)
+}
+
+func handleUnknownSection(slackClient SlackClient, section string, channel string) {
+	slackClient.PostReply(fmt.Sprintf("I don't know a section called: %v. Try faces, helps, interestings or events", section), channel, THUMBS_DOWN)
+}
+
+func handleRestError(slackClient SlackClient, err error, channel string) {
+	slackClient.PostReply(RestErrorMessage(err), channel, THUMBS_DOWN)
+}
+
+func handleItemNotFound(slackClient SlackClient, itemId string, channel string) {
+	slackClient.PostReply(fmt.Sprintf("I couldn't find an item with id: %v", itemId), channel, THUMBS_DOWN)
+}
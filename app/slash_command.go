@@ -0,0 +1,95 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"github.com/nlopes/slack"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	RESPONSE_URL_TIMEOUT = 5 * time.Second
+)
+
+type SlashCommandHandler struct {
+	SigningSecret string
+	Clock         Clock
+	Whiteboard    WhiteboardApp
+	Dispatcher    *Dispatcher
+}
+
+func (handler SlashCommandHandler) ServeHTTP(responseWriter http.ResponseWriter, req *http.Request) {
+	body, ok := VerifySlackRequest(handler.SigningSecret, handler.Clock, req)
+	if !ok {
+		http.Error(responseWriter, "invalid signature", http.StatusUnauthorized)
+		return
+	}
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		http.Error(responseWriter, "invalid payload", http.StatusBadRequest)
+		return
+	}
+
+	text := strings.TrimSpace(form.Get("text"))
+	if len(text) == 0 {
+		text = "?"
+	}
+	ev := &slack.MessageEvent{Msg: slack.Msg{Type: "message", User: form.Get("user_id"), Channel: form.Get("channel_id"), Text: "wb " + text}}
+	whiteboard := handler.Whiteboard.WithSlackClient(&SlashCommandClient{SlackClient: handler.Whiteboard.SlackClient, ResponseUrl: form.Get("response_url"), TriggerId: form.Get("trigger_id")})
+
+	if handler.Dispatcher != nil {
+		handler.Dispatcher.DispatchTask(ev.User, func() {
+			whiteboard.ParseMessageEvent(ev)
+		})
+	} else {
+		whiteboard.ParseMessageEvent(ev)
+	}
+	responseWriter.WriteHeader(http.StatusOK)
+}
+
+// SlashCommandClient sends replies to a slash command back to the requester only, while messages and saved entries
+// are still posted to the channel. Replies give up after Timeout, or RESPONSE_URL_TIMEOUT when it isn't set, so a
+// stuck response_url doesn't hold up the user's next commands.
+type SlashCommandClient struct {
+	SlackClient
+	ResponseUrl string
+	TriggerId   string
+	Timeout     time.Duration
+}
+
+type slashCommandResponse struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+func (slashClient *SlashCommandClient) PostEntry(entry *Entry, channel string, status string) (timestamp string) {
+	if strings.HasPrefix(status, THUMBS_UP) {
+		return slashClient.SlackClient.PostEntry(entry, channel, status)
+	}
+	slashClient.PostReply(entry.String(), channel, status)
+	return
+}
+
+func (slashClient *SlashCommandClient) PostReply(message string, channel string, status string) {
+	response, _ := json.Marshal(slashCommandResponse{ResponseType: "ephemeral", Text: status + message})
+	fmt.Printf("Responding to slash command:\n%v\n", string(response))
+	httpClient := &http.Client{Timeout: slashClient.timeout()}
+	resp, err := httpClient.Post(slashClient.ResponseUrl, "application/json", bytes.NewReader(response))
+	if err != nil {
+		fmt.Printf("Slash command response failed: %v\n", err)
+		return
+	}
+	resp.Body.Close()
+}
+
+func (slashClient *SlashCommandClient) timeout() time.Duration {
+	if slashClient.Timeout == 0 {
+		return RESPONSE_URL_TIMEOUT
+	}
+	return slashClient.Timeout
+}
@@ -0,0 +1,299 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/nlopes/slack"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"strconv"
+	"strings"
+)
+
+// readStandupAlias takes the name off a registration like `12 as sydney`.
+func readStandupAlias(input string) (rest string, alias string) {
+	fields := strings.Fields(input)
+	if len(fields) < 3 || strings.ToLower(fields[len(fields) - 2]) != STANDUP_ALIAS_KEYWORD {
+		return input, ""
+	}
+	alias = strings.ToLower(strings.TrimPrefix(fields[len(fields) - 1], STANDUP_SELECTOR))
+	return strings.Join(fields[:len(fields) - 2], " "), alias
+}
+
+// registerNamedStandup adds the standup to the channel under the alias, and makes it the channel's default when it
+// replaces the default, or when there isn't one yet and nobody else registers one first.
+func (whiteboard WhiteboardApp) registerNamedStandup(standup Standup, alias string, channel string) {
+	standup.Alias = alias
+	whiteboard.Store.SetNamedStandup(channel, standup)
+	if defaultStandup, ok := whiteboard.Store.GetStandup(channel); ok && defaultStandup.Alias == alias {
+		whiteboard.Store.SetStandup(channel, standup)
+	} else {
+		whiteboard.Store.SetStandupIfMissing(channel, standup)
+	}
+	whiteboard.SlackClient.PostMessage(fmt.Sprintf("Standup %v has been registered as %v! Pick it for a command like this: `wb @%v i My title`", standup.Title, alias, alias), channel, THUMBS_UP)
+}
+
+// selectedStandup is the channel's standup picked with `wb @name`, or its default one.
+func (whiteboard WhiteboardApp) selectedStandup(channel string, context CommandContext) (standup Standup, ok bool) {
+	if len(context.StandupName) == 0 {
+		return whiteboard.Store.GetStandup(channel)
+	}
+	standups, _ := whiteboard.Store.GetNamedStandups(channel)
+	for _, standup = range standups {
+		if standup.Alias == strings.ToLower(context.StandupName) {
+			return standup, true
+		}
+	}
+	return Standup{}, false
+}
+
+// channelStandups lists the channel's default standup first, followed by the other named ones.
+func (whiteboard WhiteboardApp) channelStandups(channel string) (standups []Standup) {
+	defaultStandup, hasDefault := whiteboard.Store.GetStandup(channel)
+	if hasDefault {
+		standups = append(standups, defaultStandup)
+	}
+	named, _ := whiteboard.Store.GetNamedStandups(channel)
+	for _, standup := range named {
+		if !hasDefault || standup.Alias != defaultStandup.Alias {
+			standups = append(standups, standup)
+		}
+	}
+	return
+}
+
+// entryStandup is the channel's standup the entry belongs to, so changes to it go to the right whiteboard whichever
+// standup is picked for the command.
+func (whiteboard WhiteboardApp) entryStandup(channel string, entryType EntryType, standup Standup) Standup {
+	if missingEntry(entryType) || entryType.GetEntry().StandupId == standup.Id {
+		return standup
+	}
+	for _, candidate := range whiteboard.channelStandups(channel) {
+		if candidate.Id == entryType.GetEntry().StandupId {
+			return candidate
+		}
+	}
+	return standup
+}
+
+// EntryRef names an entry along with the whiteboard it's on, as item ids are only unique on one whiteboard. It's kept
+// in the entry's card and thread.
+type EntryRef struct {
+	Backend string
+	ItemId  string
+	// AnyBackend is set for the bare item ids kept before the whiteboard was, which could be on any of them.
+	AnyBackend bool
+}
+
+func NewEntryRef(entry *Entry) EntryRef {
+	return EntryRef{Backend: entry.Backend, ItemId: entry.Id}
+}
+
+func (ref EntryRef) String() string {
+	if ref.AnyBackend {
+		return ref.ItemId
+	}
+	return ref.Backend + ":" + ref.ItemId
+}
+
+// ParseEntryRef reads an EntryRef from its String, or from a bare item id.
+func ParseEntryRef(value string) EntryRef {
+	index := strings.LastIndex(value, ":")
+	if index < 0 {
+		return EntryRef{ItemId: value, AnyBackend: true}
+	}
+	return EntryRef{Backend: value[:index], ItemId: value[index + 1:]}
+}
+
+func (ref EntryRef) onStandup(standup Standup) bool {
+	return ref.AnyBackend || ref.Backend == standup.Backend
+}
+
+// findEntry looks for the item in each of the channel's standups on its whiteboard, returning a nil entryType when none
+// has it.
+func (whiteboard WhiteboardApp) findEntry(channel string, ref EntryRef) (standup Standup, entryType EntryType, err error) {
+	for _, standup = range whiteboard.channelStandups(channel) {
+		if !ref.onStandup(standup) {
+			continue
+		}
+		var items StandupItems
+		if items, err = whiteboard.restClientFor(standup).GetStandupItems(standup.Id); err != nil {
+			return
+		}
+		if found, ok := items.Find(ref.ItemId); ok {
+			found.GetEntry().StandupId = standup.Id
+			found.GetEntry().Backend = standup.Backend
+			return standup, found, nil
+		}
+	}
+	return standup, nil, nil
+}
+
+func (whiteboard WhiteboardApp) handleStandupsCommand(_ string, ev *slack.MessageEvent, _ CommandContext) {
+	standups := whiteboard.channelStandups(ev.Channel)
+	if len(standups) == 0 {
+		handleNotRegistered(whiteboard.SlackClient, ev.Channel)
+		return
+	}
+
+	var buffer bytes.Buffer
+	buffer.WriteString("Standups registered in this channel:\n")
+	for i, standup := range standups {
+		label := fmt.Sprintf("`%v%v`", STANDUP_SELECTOR, standup.Alias)
+		if i == 0 && len(standup.Alias) == 0 {
+			label = "`default`"
+		} else if i == 0 {
+			label += " _(default)_"
+		}
+		whiteboardName := ""
+		if len(standup.Backend) > 0 {
+			whiteboardName = fmt.Sprintf(" on the %v whiteboard", standup.Backend)
+		}
+		buffer.WriteString(fmt.Sprintf("\n%v *%v* (standup %v%v)", label, standup.Title, standup.Id, whiteboardName))
+	}
+	buffer.WriteString("\n\nAdd another with `wb r <id> as <name>`, then pick it for a command like this: `wb @<name> i My title`")
+	whiteboard.SlackClient.PostReply(buffer.String(), ev.Channel, "")
+}
+
+func (whiteboard WhiteboardApp) handleStatusCommand(_ string, ev *slack.MessageEvent, context CommandContext) {
+	standup, _, _, ok := whiteboard.getEntryDetails(ev, context)
+	if !ok {
+		return
+	}
+	var buffer bytes.Buffer
+	buffer.WriteString(fmt.Sprintf("This channel is registered to standup *%v*\n", standup.Title))
+	if len(standup.Alias) > 0 {
+		buffer.WriteString(fmt.Sprintf("\nName: @%v", standup.Alias))
+	}
+	buffer.WriteString(fmt.Sprintf("\nId: %v", standup.Id))
+	buffer.WriteString(fmt.Sprintf("\nTime zone: %v", standup.TimeZone))
+	buffer.WriteString(fmt.Sprintf("\nWhiteboard: %v", whiteboard.restClientFor(standup).HostUrl()))
+	if standups := whiteboard.channelStandups(ev.Channel); len(standups) > 1 {
+		buffer.WriteString(fmt.Sprintf("\n\nThere are %v standups registered here, see them all with `wb standups`", len(standups)))
+	}
+	whiteboard.SlackClient.PostReply(buffer.String(), ev.Channel, "")
+}
+
+// handleUnregisterCommand asks to confirm removing the standup picked with `wb @name unregister`, or without a name
+// everything the channel has registered, along with its schedule and settings.
+func (whiteboard WhiteboardApp) handleUnregisterCommand(input string, ev *slack.MessageEvent, context CommandContext) {
+	standup, slackUser, _, ok := whiteboard.getEntryDetails(ev, context)
+	if !ok {
+		return
+	}
+	if input == UNREGISTER_CONFIRMATION {
+		whiteboard.handleUnregisterConfirmation(slackUser, ev)
+		return
+	}
+
+	whiteboard.Store.SetExpiring(PendingUnregisterKey(ev.Channel, slackUser.Username), strings.ToLower(context.StandupName), CONFIRMATION_EXPIRY)
+	subject := fmt.Sprintf("standup %v and everything else registered in this channel", standup.Title)
+	if len(context.StandupName) > 0 {
+		subject = fmt.Sprintf("standup %v (@%v)", standup.Title, standup.Alias)
+	}
+	whiteboard.SlackClient.PostReply(fmt.Sprintf("Are you sure you want to unregister %v? Confirm with `wb unregister yes`", subject), ev.Channel, "")
+}
+
+// handleUnregisterConfirmation removes what the user asked to unregister in this channel a few minutes ago.
+func (whiteboard WhiteboardApp) handleUnregisterConfirmation(slackUser SlackUser, ev *slack.MessageEvent) {
+	standupName, ok := whiteboard.Store.Get(PendingUnregisterKey(ev.Channel, slackUser.Username))
+	if !ok {
+		whiteboard.SlackClient.PostReply("There's nothing waiting to be unregistered. Start with `wb unregister` or `wb @name unregister` first!", ev.Channel, THUMBS_DOWN)
+		return
+	}
+	whiteboard.Store.Delete(PendingUnregisterKey(ev.Channel, slackUser.Username))
+
+	standup, ok := whiteboard.selectedStandup(ev.Channel, CommandContext{StandupName: standupName})
+	if !ok {
+		handleUnknownStandup(whiteboard.SlackClient, standupName, ev.Channel)
+		return
+	}
+	if len(standupName) > 0 {
+		whiteboard.forgetStandup(standup, ev.Channel)
+		whiteboard.unregisterNamedStandup(standup, ev.Channel)
+		return
+	}
+
+	whiteboard.forgetEntryCards(ev.Channel, whiteboard.channelStandups(ev.Channel), nil)
+	for _, key := range []string{StandupKey(ev.Channel), SectionOrderKey(ev.Channel), PostKey(ev.Channel)} {
+		whiteboard.Store.Delete(key)
+	}
+	for _, prefix := range []string{NamedStandupKey(ev.Channel, ""), ScheduleKey(ev.Channel, ""), PendingDeleteKey(ev.Channel, ""), BulkKey(ev.Channel, ""), PendingEmailKey(ev.Channel, ""), PendingUnregisterKey(ev.Channel, "")} {
+		for _, key := range whiteboard.Store.Keys(prefix) {
+			whiteboard.Store.Delete(key)
+		}
+	}
+	registration := strconv.Itoa(standup.Id)
+	if len(standup.Backend) > 0 {
+		registration = standup.Backend + " " + registration
+	}
+	whiteboard.SlackClient.PostMessage(fmt.Sprintf("Standup %v has been unregistered from this channel. Register it again with `wb r %v`", standup.Title, registration), ev.Channel, THUMBS_UP)
+}
+
+// forgetStandup drops what the channel keeps for one of its standups: the cards of its items, and the deletes, bulk
+// entries and archived post waiting on it.
+func (whiteboard WhiteboardApp) forgetStandup(standup Standup, channel string) {
+	if items, err := whiteboard.restClientFor(standup).GetStandupItems(standup.Id); err == nil {
+		whiteboard.forgetEntryCards(channel, []Standup{standup}, &items)
+	}
+	for _, key := range whiteboard.Store.Keys(PendingDeleteKey(channel, "")) {
+		username := strings.TrimPrefix(key, PendingDeleteKey(channel, ""))
+		if pending, ok := whiteboard.Store.GetPendingDelete(channel, username); ok && sameStandup(pending.Standup, standup) {
+			whiteboard.Store.Delete(key)
+		}
+	}
+	for _, key := range whiteboard.Store.Keys(BulkKey(channel, "")) {
+		username := strings.TrimPrefix(key, BulkKey(channel, ""))
+		if bulk, ok := whiteboard.Store.GetBulkEntries(channel, username); ok && sameStandup(bulk.Standup, standup) {
+			whiteboard.Store.Delete(key)
+		}
+	}
+	if post, ok := whiteboard.Store.GetArchivedPost(channel); ok && sameStandup(post.Standup, standup) {
+		whiteboard.Store.Delete(PostKey(channel))
+		whiteboard.Store.Delete(PendingEmailKey(channel, post.PostId))
+	}
+	whiteboard.Store.Delete(ScheduleKey(channel, standup.Alias))
+}
+
+// forgetEntryCards stops following the cards posted in the channel for the standups' items, or only for those among
+// items when it's given, and forgets which messages they were.
+func (whiteboard WhiteboardApp) forgetEntryCards(channel string, standups []Standup, items *StandupItems) {
+	for _, threadKey := range whiteboard.Store.Keys(ThreadKey(channel, "")) {
+		value, _ := whiteboard.Store.Get(threadKey)
+		ref := ParseEntryRef(value)
+		if items != nil {
+			if _, ok := items.Find(ref.ItemId); !ok {
+				continue
+			}
+		}
+		forgotten := false
+		for _, standup := range standups {
+			if !ref.onStandup(standup) {
+				continue
+			}
+			forgotten = true
+			if message, ok := whiteboard.Store.GetEntryMessage(standup.Backend, ref.ItemId); ok && ThreadKey(message.Channel, message.Timestamp) == threadKey {
+				whiteboard.Store.Delete(EntryMessageKey(standup.Backend, ref.ItemId))
+			}
+		}
+		if forgotten || items == nil {
+			whiteboard.Store.Delete(threadKey)
+		}
+	}
+}
+
+func sameStandup(standup Standup, other Standup) bool {
+	return standup.Id == other.Id && standup.Backend == other.Backend
+}
+
+// unregisterNamedStandup removes a named standup, handing the channel's default over to the first of the others by
+// name if it was the default.
+func (whiteboard WhiteboardApp) unregisterNamedStandup(standup Standup, channel string) {
+	whiteboard.Store.Delete(NamedStandupKey(channel, standup.Alias))
+	if defaultStandup, ok := whiteboard.Store.GetStandup(channel); ok && defaultStandup.Alias == standup.Alias {
+		whiteboard.Store.Delete(StandupKey(channel))
+		if registered, ok := whiteboard.Store.GetNamedStandups(channel); ok {
+			whiteboard.Store.SetStandupIfMissing(channel, registered[0])
+		}
+	}
+	whiteboard.SlackClient.PostMessage(fmt.Sprintf("Standup %v (@%v) has been unregistered from this channel.", standup.Title, standup.Alias), channel, THUMBS_UP)
+}
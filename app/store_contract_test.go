@@ -0,0 +1,289 @@
+package app_test
+
+import (
+	"fmt"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/garyburd/redigo/redis"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+	"github.com/pivotal-sydney/whiteboardbot/spec"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// behavesLikeAStore is the contract every Store backend has to keep. newStore is called before each spec, returning
+// the store and a way to move its time forward.
+func behavesLikeAStore(newStore func() (store Store, elapse func(time.Duration))) {
+	var (
+		store  Store
+		elapse func(time.Duration)
+	)
+
+	BeforeEach(func() {
+		store, elapse = newStore()
+	})
+
+	It("should be at the current schema version", func() {
+		Expect(store.SchemaVersion()).To(Equal(SCHEMA_VERSION))
+	})
+
+	It("should get, set and delete values", func() {
+		_, ok := store.Get("wb:v3:user:aleung:delete")
+		Expect(ok).To(BeFalse())
+
+		store.Set("wb:v3:user:aleung:delete", "1")
+		store.Set("wb:v3:user:aleung:delete", "2")
+		value, ok := store.Get("wb:v3:user:aleung:delete")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("2"))
+
+		store.Delete("wb:v3:user:aleung:delete")
+		_, ok = store.Get("wb:v3:user:aleung:delete")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should list keys by prefix, taking the prefix literally", func() {
+		store.Set(ThreadKey("C123", "1.1"), "1")
+		store.Set(ThreadKey("C123", "1.2"), "2")
+		store.Set(ThreadKey("C999", "1.1"), "3")
+		store.Set("wb:v3:channel:C1*:thread:1.1", "4")
+
+		Expect(store.Keys(ThreadKey("C123", ""))).To(ConsistOf("wb:v3:channel:C123:thread:1.1", "wb:v3:channel:C123:thread:1.2"))
+		Expect(store.Keys("wb:v3:channel:C1*")).To(Equal([]string{"wb:v3:channel:C1*:thread:1.1"}))
+		Expect(store.Keys("wb:v3:channel:C456")).To(BeEmpty())
+	})
+
+	It("should keep standups and schedules", func() {
+		_, ok := store.GetStandup("C123")
+		Expect(ok).To(BeFalse())
+
+		store.SetStandup("C123", model.Standup{Id: 1, Title: "Sydney", TimeZone: "Australia/Sydney"})
+		store.SetNamedStandup("C123", model.Standup{Id: 12, Title: "Melbourne", Alias: "melbourne"})
+		store.SetNamedStandup("C123", model.Standup{Id: 3, Title: "Brisbane", Alias: "brisbane"})
+		store.SetSchedule("C123", "", model.Schedule{Days: []time.Weekday{time.Monday}, Hour: 9, Minute: 5})
+		store.SetSchedule("C123", "melbourne", model.Schedule{Days: []time.Weekday{time.Friday}, Hour: 10})
+
+		standup, ok := store.GetStandup("C123")
+		Expect(ok).To(BeTrue())
+		Expect(standup).To(Equal(model.Standup{Id: 1, Title: "Sydney", TimeZone: "Australia/Sydney"}))
+		standups, ok := store.GetNamedStandups("C123")
+		Expect(ok).To(BeTrue())
+		Expect(standups).To(Equal([]model.Standup{{Id: 3, Title: "Brisbane", Alias: "brisbane"}, {Id: 12, Title: "Melbourne", Alias: "melbourne"}}))
+		schedule, ok := store.GetSchedule("C123", "")
+		Expect(ok).To(BeTrue())
+		Expect(schedule).To(Equal(model.Schedule{Days: []time.Weekday{time.Monday}, Hour: 9, Minute: 5}))
+		schedule, ok = store.GetSchedule("C123", "melbourne")
+		Expect(ok).To(BeTrue())
+		Expect(schedule).To(Equal(model.Schedule{Days: []time.Weekday{time.Friday}, Hour: 10}))
+		_, ok = store.GetSchedule("C123", "sydney")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should only set a value that isn't there yet", func() {
+		Expect(store.SetIfMissing("wb:v3:channel:C123:standup", "first", 0)).To(BeTrue())
+		Expect(store.SetIfMissing("wb:v3:channel:C123:standup", "second", 0)).To(BeFalse())
+		value, _ := store.Get("wb:v3:channel:C123:standup")
+		Expect(value).To(Equal("first"))
+
+		store.SetExpiring("wb:v3:channel:C123:seen:1", "1", time.Minute)
+		elapse(time.Minute)
+		Expect(store.SetIfMissing("wb:v3:channel:C123:seen:1", "2", 0)).To(BeTrue())
+	})
+
+	It("should expire a value set when it wasn't there", func() {
+		Expect(store.SetIfMissing("wb:v3:channel:C123:seen:1", "1", time.Minute)).To(BeTrue())
+		elapse(time.Minute - time.Second)
+		Expect(store.SetIfMissing("wb:v3:channel:C123:seen:1", "2", time.Minute)).To(BeFalse())
+
+		elapse(time.Second)
+		Expect(store.SetIfMissing("wb:v3:channel:C123:seen:1", "3", time.Minute)).To(BeTrue())
+		value, _ := store.Get("wb:v3:channel:C123:seen:1")
+		Expect(value).To(Equal("3"))
+	})
+
+	It("should only set the standup when there isn't one yet", func() {
+		Expect(store.SetStandupIfMissing("C123", model.Standup{Id: 1, Title: "Sydney"})).To(BeTrue())
+		Expect(store.SetStandupIfMissing("C123", model.Standup{Id: 2, Title: "Melbourne"})).To(BeFalse())
+		standup, _ := store.GetStandup("C123")
+		Expect(standup.Title).To(Equal("Sydney"))
+	})
+
+	It("should take a value only once", func() {
+		store.SetExpiring("wb:v3:channel:C123:email:7", "aleung", time.Minute)
+		value, ok := store.Take("wb:v3:channel:C123:email:7")
+		Expect(ok).To(BeTrue())
+		Expect(value).To(Equal("aleung"))
+		_, ok = store.Take("wb:v3:channel:C123:email:7")
+		Expect(ok).To(BeFalse())
+		_, ok = store.Get("wb:v3:channel:C123:email:7")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should expire values set to expire", func() {
+		store.SetExpiring("wb:v3:channel:C123:seen:1", "1", time.Minute)
+		elapse(time.Minute - time.Second)
+		_, ok := store.Get("wb:v3:channel:C123:seen:1")
+		Expect(ok).To(BeTrue())
+
+		elapse(time.Second)
+		_, ok = store.Get("wb:v3:channel:C123:seen:1")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should keep pending deletes with their standup", func() {
+		store.SetPendingDelete("C123", "aleung", PendingDelete{ItemId: "42", Standup: model.Standup{Id: 12, Backend: "singapore"}})
+		pending, ok := store.GetPendingDelete("C123", "aleung")
+		Expect(ok).To(BeTrue())
+		Expect(pending).To(Equal(PendingDelete{ItemId: "42", Standup: model.Standup{Id: 12, Backend: "singapore"}}))
+		_, ok = store.GetPendingDelete("C999", "aleung")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should keep archived posts with their standup", func() {
+		store.SetArchivedPost("C123", ArchivedPost{PostId: "7", Standup: model.Standup{Id: 12, Backend: "singapore"}})
+		post, ok := store.GetArchivedPost("C123")
+		Expect(ok).To(BeTrue())
+		Expect(post).To(Equal(ArchivedPost{PostId: "7", Standup: model.Standup{Id: 12, Backend: "singapore"}}))
+		_, ok = store.GetArchivedPost("C999")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should keep bulk entries with their standup", func() {
+		store.SetBulkEntries("C123", "aleung", BulkEntries{ItemIds: []string{"42", "43"}, Standup: model.Standup{Id: 12, Backend: "singapore"}})
+		bulk, ok := store.GetBulkEntries("C123", "aleung")
+		Expect(ok).To(BeTrue())
+		Expect(bulk).To(Equal(BulkEntries{ItemIds: []string{"42", "43"}, Standup: model.Standup{Id: 12, Backend: "singapore"}}))
+		_, ok = store.GetBulkEntries("C999", "aleung")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should keep entries, their messages and their history", func() {
+		store.SetEntry("aleung", model.Interesting{Entry: &model.Entry{Id: "42", Title: "Something interesting", ItemKind: "Interesting"}})
+		store.SetEntryMessage("", "42", EntryMessage{Channel: "C123", Timestamp: "1420156800.000100"})
+		store.SetEntryHistory("", "42", model.EntryHistory{Changes: []model.EntryChange{{Author: "Andrew Leung"}}, Position: 1})
+
+		entryType, ok := store.GetEntry("aleung")
+		Expect(ok).To(BeTrue())
+		Expect(entryType.GetEntry().Title).To(Equal("Something interesting"))
+		message, ok := store.GetEntryMessage("", "42")
+		Expect(ok).To(BeTrue())
+		Expect(message).To(Equal(EntryMessage{Channel: "C123", Timestamp: "1420156800.000100"}))
+		history, ok := store.GetEntryHistory("", "42")
+		Expect(ok).To(BeTrue())
+		Expect(history.Changes[0].Author).To(Equal("Andrew Leung"))
+		Expect(history.Position).To(Equal(1))
+	})
+
+	It("should keep the messages and history of items on different whiteboards apart", func() {
+		store.SetEntryMessage("", "42", EntryMessage{Channel: "C123", Timestamp: "1420156800.000100"})
+		store.SetEntryHistory("", "42", model.EntryHistory{Changes: []model.EntryChange{{Author: "Andrew Leung"}}, Position: 1})
+
+		_, ok := store.GetEntryMessage("singapore", "42")
+		Expect(ok).To(BeFalse())
+		_, ok = store.GetEntryHistory("singapore", "42")
+		Expect(ok).To(BeFalse())
+		Expect(EntryMessageKey("", "42")).To(Equal("wb:v3:item:default:42:message"))
+		Expect(HistoryKey("singapore", "42")).To(Equal("wb:v3:item:singapore:42:history"))
+	})
+
+	It("should expire entries, their messages, their history and pending confirmations, but nothing else", func() {
+		store.SetStandup("C123", model.Standup{Id: 1, Title: "Sydney"})
+		store.SetEntry("aleung", model.Interesting{Entry: &model.Entry{Id: "42", Title: "Something interesting", ItemKind: "Interesting"}})
+		store.SetEntryMessage("", "42", EntryMessage{Channel: "C123", Timestamp: "1420156800.000100"})
+		store.SetEntryHistory("", "42", model.EntryHistory{Changes: []model.EntryChange{{Author: "Andrew Leung"}}, Position: 1})
+		store.SetPendingDelete("C123", "aleung", PendingDelete{ItemId: "42", Standup: model.Standup{Id: 1}})
+
+		elapse(CONFIRMATION_EXPIRY)
+		_, ok := store.GetPendingDelete("C123", "aleung")
+		Expect(ok).To(BeFalse())
+
+		elapse(ENTRY_EXPIRY - CONFIRMATION_EXPIRY - time.Minute)
+		_, ok = store.GetEntry("aleung")
+		Expect(ok).To(BeTrue())
+
+		elapse(time.Minute)
+		_, ok = store.GetEntry("aleung")
+		Expect(ok).To(BeFalse())
+		_, ok = store.GetEntryHistory("", "42")
+		Expect(ok).To(BeFalse())
+		_, ok = store.GetEntryMessage("", "42")
+		Expect(ok).To(BeFalse())
+		Expect(store.Keys(EntryKey("aleung"))).To(BeEmpty())
+		_, ok = store.GetStandup("C123")
+		Expect(ok).To(BeTrue())
+	})
+
+	It("should handle being used from several goroutines at once", func() {
+		var waitGroup sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			waitGroup.Add(1)
+			go func(i int) {
+				defer waitGroup.Done()
+				store.Set(PostKey(fmt.Sprintf("C%v", i)), "1")
+				store.Get(PostKey(fmt.Sprintf("C%v", i)))
+				store.Keys(KEY_NAMESPACE)
+			}(i)
+		}
+		waitGroup.Wait()
+		Expect(store.Keys(KEY_NAMESPACE + "channel:")).To(HaveLen(20))
+	})
+}
+
+var _ = Describe("Store backends", func() {
+	Describe("RealStore", func() {
+		var server *miniredis.Miniredis
+		var store *RealStore
+
+		BeforeEach(func() {
+			var err error
+			server, err = miniredis.Run()
+			Expect(err).To(BeNil())
+			store = NewRealStore(&redis.Pool{Dial: func() (redis.Conn, error) {
+				return redis.Dial("tcp", server.Addr())
+			}})
+			_, err = store.Migrate()
+			Expect(err).To(BeNil())
+		})
+
+		AfterEach(func() {
+			store.Pool.Close()
+			server.Close()
+		})
+
+		behavesLikeAStore(func() (Store, func(time.Duration)) {
+			return store, server.FastForward
+		})
+	})
+
+	Describe("MemoryStore", func() {
+		behavesLikeAStore(func() (Store, func(time.Duration)) {
+			clock := &spec.FakeClock{CurrentTime: spec.MockClock{}.Now()}
+			return NewMemoryStore(clock), clock.Advance
+		})
+	})
+
+	Describe("BoltStore", func() {
+		var directory string
+		var store *BoltStore
+
+		AfterEach(func() {
+			store.Close()
+			os.RemoveAll(directory)
+		})
+
+		behavesLikeAStore(func() (Store, func(time.Duration)) {
+			var err error
+			directory, err = ioutil.TempDir("", "whiteboardbot")
+			Expect(err).To(BeNil())
+			clock := &spec.FakeClock{CurrentTime: spec.MockClock{}.Now()}
+			store, err = NewBoltStore(filepath.Join(directory, "whiteboardbot.db"), clock)
+			Expect(err).To(BeNil())
+			return store, clock.Advance
+		})
+	})
+})
@@ -0,0 +1,79 @@
+package app
+
+import (
+	"github.com/nlopes/slack"
+)
+
+// THREAD_COMMANDS can be replied in an entry's thread to update that entry, with or without `wb` in front.
+var THREAD_COMMANDS = []string{"name", "title", "body", "date", "public", "private"}
+
+// threadEntry finds the entry whose card the message replies to in a thread.
+func (whiteboard WhiteboardApp) threadEntry(ev *slack.MessageEvent) (ref EntryRef, ok bool) {
+	if len(ev.ThreadTimestamp) == 0 || ev.ThreadTimestamp == ev.Timestamp {
+		return
+	}
+	value, ok := whiteboard.Store.Get(ThreadKey(ev.Channel, ev.ThreadTimestamp))
+	if !ok || len(value) == 0 {
+		return EntryRef{}, false
+	}
+	return ParseEntryRef(value), true
+}
+
+// DispatchKey queues replies that update an entry from its thread behind each other, whoever sends them, and
+// everything else behind the user's other messages. Those queues still meet at the entry, like a reply and the
+// owner's `wb b`, so every update to an entry also takes its turn with lockEntry.
+func (whiteboard WhiteboardApp) DispatchKey(ev *slack.MessageEvent) string {
+	if ref, inThread := whiteboard.threadEntry(ev); inThread {
+		if _, _, ok := whiteboard.threadUpdate(getInputString(ev)); ok {
+			return "item:" + ref.String()
+		}
+	}
+	return ev.User
+}
+
+// handleThreadReply updates the entry from a reply in its thread, whoever is replying, and leaves the replier's own
+// current entry alone. Only replies starting with one of the THREAD_COMMANDS count, so chatting in the thread doesn't
+// change the entry; other `wb` commands are left to run as usual.
+func (whiteboard WhiteboardApp) handleThreadReply(ref EntryRef, input string, ev *slack.MessageEvent) (handled bool) {
+	command, rest, ok := whiteboard.threadUpdate(input)
+	if !ok {
+		return false
+	}
+
+	if _, _, _, ok := whiteboard.getEntryDetails(ev, CommandContext{}); !ok {
+		return true
+	}
+	defer whiteboard.lockEntry(ref.ItemId)()
+	_, entryType, err := whiteboard.findEntry(ev.Channel, ref)
+	if err != nil {
+		handleRestError(whiteboard.SlackClient, err, ev.Channel)
+		return true
+	}
+	if missingEntry(entryType) {
+		handleItemNotFound(whiteboard.SlackClient, ref.ItemId, ev.Channel)
+		return true
+	}
+	command.Handler(whiteboard, rest, ev, CommandContext{Entry: entryType})
+	return true
+}
+
+// threadUpdate reads a thread reply as one of the THREAD_COMMANDS and its input.
+func (whiteboard WhiteboardApp) threadUpdate(input string) (command Command, rest string, ok bool) {
+	keyword, rest := readNextCommand(input)
+	viaWb := matches(keyword, "wb")
+	if viaWb {
+		keyword, rest = readNextCommand(rest)
+	}
+	command, ok = whiteboard.threadCommand(keyword, viaWb)
+	return
+}
+
+// threadCommand finds the command a thread reply starts with. Abbreviations only count after `wb`, so a plain reply
+// has to start with the whole command name, like `date tomorrow`, to be read as one.
+func (whiteboard WhiteboardApp) threadCommand(keyword string, viaWb bool) (command Command, ok bool) {
+	command, _, ok = whiteboard.Commands.Resolve(keyword)
+	if !ok || !containsString(THREAD_COMMANDS, command.Name) {
+		return command, false
+	}
+	return command, viaWb || keyword == command.Name
+}
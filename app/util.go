@@ -33,6 +33,15 @@ func readNextCommand(input string) (keyword string, newInput string) {
 	return
 }
 
+func containsString(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
+}
+
 func missingEntry(entryType EntryType) bool {
 	return entryType == nil
 }
\ No newline at end of file
@@ -3,10 +3,10 @@ import (
 	. "github.com/pivotal-sydney/whiteboardbot/model"
 	"fmt"
 	"github.com/nlopes/slack"
-	"time"
 	"strings"
 	"strconv"
 	"regexp"
+	"bytes"
 )
 
 type WhiteboardApp struct {
@@ -14,101 +14,170 @@ type WhiteboardApp struct {
 	RestClient  RestClient
 	Clock       Clock
 	Store       Store
-	EntryMap    map[string]EntryType
-	CommandMap  map[string]func(input string, ev *slack.MessageEvent)
+	Commands    *CommandRegistry
+	EntryLocks  *EntryLocks
 }
 
 func NewWhiteboard(slackClient SlackClient, restClient RestClient, clock Clock, store Store) (whiteboard WhiteboardApp) {
 	whiteboard = WhiteboardApp{SlackClient: slackClient, Clock: clock, RestClient: restClient}
 	whiteboard.Store = store
-	whiteboard.EntryMap = make(map[string]EntryType)
-	whiteboard.CommandMap = make(map[string]func(input string, ev *slack.MessageEvent))
+	whiteboard.Commands = NewCommandRegistry()
+	whiteboard.EntryLocks = NewEntryLocks()
 	whiteboard.init()
 	return
 }
 
 func (whiteboard WhiteboardApp) init() {
-	whiteboard.registerCommand("register", whiteboard.handleRegistrationCommand)
-	whiteboard.registerCommand("?", whiteboard.handleUsageCommand)
-	whiteboard.registerCommand("faces", whiteboard.handleFacesCommand)
-	whiteboard.registerCommand("helps", whiteboard.handleHelpsCommand)
-	whiteboard.registerCommand("interestings", whiteboard.handleInterestingsCommand)
-	whiteboard.registerCommand("events", whiteboard.handleEventsCommand)
-	whiteboard.registerCommand("name", whiteboard.handleUpdateNameTitleCommand)
-	whiteboard.registerCommand("title", whiteboard.handleUpdateNameTitleCommand)
-	whiteboard.registerCommand("body", whiteboard.handleUpdateBodyCommand)
-	whiteboard.registerCommand("date", whiteboard.handleUpdateDateCommand)
-	whiteboard.registerCommand("present", whiteboard.handlePresentCommand)
+	whiteboard.registerCommand("register", WhiteboardApp.handleRegistrationCommand, "r")
+	whiteboard.registerCommand("?", WhiteboardApp.handleUsageCommand)
+	whiteboard.registerCommand("faces", WhiteboardApp.handleFacesCommand, "f")
+	whiteboard.registerCommand("helps", WhiteboardApp.handleHelpsCommand, "h")
+	whiteboard.registerCommand("interestings", WhiteboardApp.handleInterestingsCommand, "i")
+	whiteboard.registerCommand("events", WhiteboardApp.handleEventsCommand, "e")
+	whiteboard.registerCommand("name", WhiteboardApp.handleUpdateNameTitleCommand, "n")
+	whiteboard.registerCommand("title", WhiteboardApp.handleUpdateNameTitleCommand, "t")
+	whiteboard.registerCommand("body", WhiteboardApp.handleUpdateBodyCommand, "b")
+	whiteboard.registerCommand("date", WhiteboardApp.handleUpdateDateCommand, "d")
+	whiteboard.registerCommand("public", WhiteboardApp.handlePublicCommand)
+	whiteboard.registerCommand("private", WhiteboardApp.handlePrivateCommand)
+	whiteboard.registerCommand("present", WhiteboardApp.handlePresentCommand, "p")
+	whiteboard.registerCommand("delete", WhiteboardApp.handleDeleteCommand)
+	whiteboard.registerCommand("edit", WhiteboardApp.handleEditCommand)
+	whiteboard.registerCommand("new", WhiteboardApp.handleNewCommand)
+	whiteboard.registerCommand("archive", WhiteboardApp.handleArchiveCommand)
+	whiteboard.registerCommand("email", WhiteboardApp.handleEmailCommand)
+	whiteboard.registerCommand("schedule", WhiteboardApp.handleScheduleCommand)
+	whiteboard.registerCommand("bulk", WhiteboardApp.handleBulkCommand)
+	whiteboard.registerCommand("undo", WhiteboardApp.handleUndoCommand)
+	whiteboard.registerCommand("redo", WhiteboardApp.handleRedoCommand)
+	whiteboard.registerCommand("history", WhiteboardApp.handleHistoryCommand)
+	whiteboard.registerCommand("standups", WhiteboardApp.handleStandupsCommand)
+	whiteboard.registerCommand("status", WhiteboardApp.handleStatusCommand)
+	whiteboard.registerCommand("unregister", WhiteboardApp.handleUnregisterCommand)
 }
 
-func (whiteboard WhiteboardApp) registerCommand(command string, callback func(input string, ev *slack.MessageEvent)) {
-	whiteboard.CommandMap[command] = callback
+func (whiteboard WhiteboardApp) registerCommand(command string, callback func(whiteboard WhiteboardApp, input string, ev *slack.MessageEvent, context CommandContext), aliases ...string) {
+	whiteboard.Commands.Register(command, callback, aliases...)
 }
 
+func (whiteboard WhiteboardApp) WithSlackClient(slackClient SlackClient) WhiteboardApp {
+	whiteboard.SlackClient = slackClient
+	return whiteboard
+}
+
+// ParseMessageEvent handles a message as its user's next command. A user's messages have to be handled one at a time,
+// so they come through a Dispatcher.
 func (whiteboard WhiteboardApp) ParseMessageEvent(ev *slack.MessageEvent) {
 	input := getInputString(ev)
 	input = whiteboard.replaceIdsWithNames(input)
 
-	command, input := readNextCommand(input)
-	if !matches(command, "wb") {
+	ref, inThread := whiteboard.threadEntry(ev)
+	command, rest := readNextCommand(input)
+	if !matches(command, "wb") && !inThread {
+		return
+	}
+	if !whiteboard.firstDelivery(ev) {
 		return
 	}
 
-	command, input = readNextCommand(input)
+	if inThread && whiteboard.handleThreadReply(ref, input, ev) {
+		return
+	}
+	if !matches(command, "wb") {
+		return
+	}
+	command, input = readNextCommand(rest)
 	whiteboard.handleCommand(command, input, ev)
 }
+
+// firstDelivery remembers the message for a while, so the copy that arrives over RTM and the Events API at once, or
+// again when Slack retries, is only handled once. The copies don't always wait in the same Dispatcher queue, as an
+// app_mention in an entry's thread is queued by the entry while its message copy is queued by the user, so only the
+// copy that sets the key first goes ahead.
+func (whiteboard WhiteboardApp) firstDelivery(ev *slack.MessageEvent) bool {
+	if len(ev.Timestamp) == 0 {
+		return true
+	}
+	return whiteboard.Store.SetIfMissing(MessageSeenKey(ev.Channel, ev.Timestamp), ev.User, SEEN_EXPIRY)
+}
+
 func (whiteboard WhiteboardApp) handleCommand(command, input string, ev *slack.MessageEvent) {
-	for key, _ := range whiteboard.CommandMap {
-		if matches(command, key) {
-			callback := whiteboard.CommandMap[key]
-			callback(input, ev)
-			return
-		}
+	var context CommandContext
+	if len(command) > 1 && strings.HasPrefix(command, STANDUP_SELECTOR) {
+		context.StandupName = strings.TrimPrefix(command, STANDUP_SELECTOR)
+		command, input = readNextCommand(input)
+	}
+	if len(command) > 1 && strings.HasSuffix(command, PUBLIC_SUFFIX) {
+		command = strings.TrimSuffix(command, PUBLIC_SUFFIX)
+		context.Public = true
+	}
+	resolved, candidates, ok := whiteboard.Commands.Resolve(command)
+	switch {
+	case ok:
+		resolved.Handler(whiteboard, input, ev, context)
+	case len(candidates) > 1:
+		whiteboard.handleAmbiguousCommand(command, candidates, ev)
+	default:
+		whiteboard.handleDefault(input, ev, context)
 	}
-	whiteboard.handleDefault(input, ev)
 }
 
-func (whiteboard WhiteboardApp) handleFacesCommand(name string, ev *slack.MessageEvent) {
-	whiteboard.handleCreateCommand(name, ev, NewFace)
+func (whiteboard WhiteboardApp) handleFacesCommand(name string, ev *slack.MessageEvent, context CommandContext) {
+	whiteboard.handleCreateCommand(name, ev, context, NewFace)
 }
 
-func (whiteboard WhiteboardApp) handleHelpsCommand(title string, ev *slack.MessageEvent) {
-	whiteboard.handleCreateCommand(title, ev, NewHelp)
+func (whiteboard WhiteboardApp) handleHelpsCommand(title string, ev *slack.MessageEvent, context CommandContext) {
+	whiteboard.handleCreateCommand(title, ev, context, NewHelp)
 }
 
-func (whiteboard WhiteboardApp) handleInterestingsCommand(title string, ev *slack.MessageEvent) {
-	whiteboard.handleCreateCommand(title, ev, NewInteresting)
+func (whiteboard WhiteboardApp) handleInterestingsCommand(title string, ev *slack.MessageEvent, context CommandContext) {
+	whiteboard.handleCreateCommand(title, ev, context, NewInteresting)
 }
 
-func (whiteboard WhiteboardApp) handleEventsCommand(title string, ev *slack.MessageEvent) {
-	whiteboard.handleCreateCommand(title, ev, NewEvent)
+func (whiteboard WhiteboardApp) handleEventsCommand(title string, ev *slack.MessageEvent, context CommandContext) {
+	whiteboard.handleCreateCommand(title, ev, context, NewEvent)
 }
 
-func (whiteboard WhiteboardApp) handleCreateCommand(title string, ev *slack.MessageEvent, createEntryCallback func(clock Clock, author string, title string, standup Standup) (entryType interface{})) {
-	standup, slackUser, _, ok := whiteboard.getEntryDetails(ev)
+func (whiteboard WhiteboardApp) handleCreateCommand(input string, ev *slack.MessageEvent, context CommandContext, createEntryCallback func(clock Clock, author string, title string, standup Standup) (entryType interface{})) {
+	standup, slackUser, _, ok := whiteboard.getEntryDetails(ev, context)
 	if !ok {
 		return
 	}
-	if len(title) == 0 {
+	entryInput, err := ParseEntryInput(input)
+	if err != nil {
+		whiteboard.SlackClient.PostReply(err.Error(), ev.Channel, THUMBS_DOWN)
+		return
+	}
+	if len(entryInput.Title) == 0 {
 		whiteboard.handleMissingTitle(ev.Channel)
 		return
 	}
 
-	entryType := createEntryCallback(whiteboard.Clock, slackUser.Author, title, standup).(EntryType)
-
-	whiteboard.EntryMap[slackUser.Username] = entryType
+	entryType := createEntryCallback(whiteboard.Clock, slackUser.Author, entryInput.Title, standup).(EntryType)
+	entry := entryType.GetEntry()
+	entry.Public = context.Public || entryInput.Public
+	entry.Body = entryInput.Body
+	if len(entryInput.Date) > 0 {
+		date, err := ParseDate(whiteboard.Clock, entryInput.Date, standup.Location())
+		if err != nil {
+			whiteboard.SlackClient.PostReply(fmt.Sprintf("%v\nUse a date like YYYY-MM-DD, 3 Dec, tomorrow or next friday", err), ev.Channel, THUMBS_DOWN)
+			return
+		}
+		entry.Date = date.Format(DATE_FORMAT)
+	}
 
-	if ev.Upload {
-		entryType.GetEntry().Body = fmt.Sprintf("%v\n<img src=\"%v\" style=\"max-width: 500px\">", ev.File.InitialComment.Comment, ev.File.Permalink)
+	if file, ok := uploadedFile(ev); ok {
+		entryType.GetEntry().Body = fmt.Sprintf("%v\n<img src=\"%v\" style=\"max-width: 500px\">", file.InitialComment.Comment, file.Permalink)
 	}
 
-	whiteboard.validateAndPost(entryType, ev)
+	whiteboard.validateAndPost(standup, entryType, ev)
+	whiteboard.Store.SetEntry(slackUser.Username, entryType)
 }
 
-func (whiteboard WhiteboardApp) handleUpdateNameTitleCommand(title string, ev *slack.MessageEvent) {
-	whiteboard.handleUpdateCommand(title, ev, func(entryType EntryType, title string) (finished bool) {
+func (whiteboard WhiteboardApp) handleUpdateNameTitleCommand(title string, ev *slack.MessageEvent, context CommandContext) {
+	whiteboard.handleUpdateCommand(title, ev, context, func(entryType EntryType, title string) (finished bool) {
 		if len(title) == 0 {
-			whiteboard.SlackClient.PostMessage("Oi! The title/name can't be empty!", ev.Channel, THUMBS_DOWN)
+			whiteboard.SlackClient.PostReply("Oi! The title/name can't be empty!", ev.Channel, THUMBS_DOWN)
 			finished = true
 		} else {
 			entryType.GetEntry().Title = title
@@ -117,33 +186,49 @@ func (whiteboard WhiteboardApp) handleUpdateNameTitleCommand(title string, ev *s
 	})
 }
 
-func (whiteboard WhiteboardApp) handleUpdateBodyCommand(body string, ev *slack.MessageEvent) {
-	whiteboard.handleUpdateCommand(body, ev, func(entryType EntryType, body string) (finished bool) {
+func (whiteboard WhiteboardApp) handleUpdateBodyCommand(body string, ev *slack.MessageEvent, context CommandContext) {
+	whiteboard.handleUpdateCommand(body, ev, context, func(entryType EntryType, body string) (finished bool) {
 		switch entryType.(type) {
 		default:
 			entryType.GetEntry().Body = body
 		case Face:
-			whiteboard.SlackClient.PostMessage("Face does not have a body! " + randomInsult(), ev.Channel, THUMBS_DOWN)
+			whiteboard.SlackClient.PostReply("Face does not have a body! " + randomInsult(), ev.Channel, THUMBS_DOWN)
 			finished = true
 		}
 		return
 	})
 }
 
-func (whiteboard WhiteboardApp) handleUpdateDateCommand(date string, ev *slack.MessageEvent) {
-	whiteboard.handleUpdateCommand(date, ev, func(entryType EntryType, input string) (finished bool) {
-		if parsedDate, err := time.Parse(DATE_FORMAT, input); err == nil {
+func (whiteboard WhiteboardApp) handleUpdateDateCommand(date string, ev *slack.MessageEvent, context CommandContext) {
+	whiteboard.handleUpdateCommand(date, ev, context, func(entryType EntryType, input string) (finished bool) {
+		standup, _ := whiteboard.selectedStandup(ev.Channel, context)
+		standup = whiteboard.entryStandup(ev.Channel, entryType, standup)
+		if parsedDate, err := ParseDate(whiteboard.Clock, input, standup.Location()); err == nil {
 			entryType.GetEntry().Date = parsedDate.Format(DATE_FORMAT)
 		} else {
-			whiteboard.SlackClient.PostEntry(entryType.GetEntry(), ev.Channel, THUMBS_DOWN + "Date not set, use YYYY-MM-DD as date format\n")
+			whiteboard.SlackClient.PostEntry(entryType.GetEntry(), ev.Channel, THUMBS_DOWN + "Date not set, use a date like YYYY-MM-DD, 3 Dec, tomorrow or next friday\n")
 			finished = true
 		}
 		return
 	})
 }
 
-func (whiteboard WhiteboardApp) handleUpdateCommand(detail string, ev *slack.MessageEvent, updateCallback func(entryType EntryType, detail string) (finished bool)) {
-	_, _, entryType, ok := whiteboard.getEntryDetails(ev)
+func (whiteboard WhiteboardApp) handlePublicCommand(_ string, ev *slack.MessageEvent, context CommandContext) {
+	whiteboard.handleUpdateCommand("", ev, context, func(entryType EntryType, _ string) (finished bool) {
+		entryType.GetEntry().Public = true
+		return
+	})
+}
+
+func (whiteboard WhiteboardApp) handlePrivateCommand(_ string, ev *slack.MessageEvent, context CommandContext) {
+	whiteboard.handleUpdateCommand("", ev, context, func(entryType EntryType, _ string) (finished bool) {
+		entryType.GetEntry().Public = false
+		return
+	})
+}
+
+func (whiteboard WhiteboardApp) handleUpdateCommand(detail string, ev *slack.MessageEvent, context CommandContext, updateCallback func(entryType EntryType, detail string) (finished bool)) {
+	standup, slackUser, entryType, ok := whiteboard.getEntryDetails(ev, context)
 	if !ok {
 		return
 	}
@@ -152,116 +237,464 @@ func (whiteboard WhiteboardApp) handleUpdateCommand(detail string, ev *slack.Mes
 		return
 	}
 
+	entry := entryType.GetEntry()
+	standup = whiteboard.entryStandup(ev.Channel, entryType, standup)
+	if context.Entry == nil && len(entry.Id) > 0 {
+		// The entry may have been changed from its card or thread since the user last touched it.
+		defer whiteboard.lockEntry(entry.Id)()
+		whiteboard.catchUpWithCard(standup, entry)
+	}
+	before := FieldsOf(entry)
 	if updateCallback(entryType, detail) {
 		return
 	}
 
-	whiteboard.validateAndPost(entryType, ev)
+	existingEntry := len(entry.Id) > 0
+	if whiteboard.validateAndPost(standup, entryType, ev) && existingEntry {
+		whiteboard.recordChange(standup, slackUser, entry, before)
+	}
+	if context.Entry == nil || whiteboard.isCurrentEntry(slackUser, entry) {
+		whiteboard.Store.SetEntry(slackUser.Username, entryType)
+	}
+}
+
+// lockEntry keeps others from updating the entry until the returned func is called. Updates coming from the entry's
+// card or thread take it before they read the entry, and the user's own updates before they catch up with the card.
+func (whiteboard WhiteboardApp) lockEntry(itemId string) (unlock func()) {
+	if whiteboard.EntryLocks == nil {
+		return func() {}
+	}
+	return whiteboard.EntryLocks.Lock(itemId)
+}
+
+// catchUpWithCard brings the user's copy of the entry up to date with what its card shows.
+func (whiteboard WhiteboardApp) catchUpWithCard(standup Standup, entry *Entry) {
+	if message, ok := whiteboard.Store.GetEntryMessage(standup.Backend, entry.Id); ok && message.Fields != nil {
+		message.Fields.ApplyTo(entry)
+	}
+}
+
+// catchUpCard keeps what the entry's card shows up to date with the entry read from the whiteboard, so the entry isn't
+// taken back to what the card showed when it's next updated.
+func (whiteboard WhiteboardApp) catchUpCard(standup Standup, entry *Entry) {
+	if message, ok := whiteboard.Store.GetEntryMessage(standup.Backend, entry.Id); ok {
+		fields := FieldsOf(entry)
+		message.Fields = &fields
+		whiteboard.Store.SetEntryMessage(standup.Backend, entry.Id, message)
+	}
+}
+
+// isCurrentEntry checks whether the user is working on the entry, so updates made to it elsewhere, like from its
+// thread, keep their copy up to date.
+func (whiteboard WhiteboardApp) isCurrentEntry(slackUser SlackUser, entry *Entry) bool {
+	current, ok := whiteboard.Store.GetEntry(slackUser.Username)
+	return ok && !missingEntry(current) && len(entry.Id) > 0 && current.GetEntry().Id == entry.Id
+}
+
+func (whiteboard WhiteboardApp) handleDeleteCommand(itemId string, ev *slack.MessageEvent, context CommandContext) {
+	standup, slackUser, entryType, ok := whiteboard.getEntryDetails(ev, context)
+	if !ok {
+		return
+	}
+	if itemId == DELETE_CONFIRMATION {
+		whiteboard.handleDeleteConfirmation(slackUser, entryType, ev)
+		return
+	}
+
+	if len(itemId) > 0 {
+		items, err := whiteboard.restClientFor(standup).GetStandupItems(standup.Id)
+		if err != nil {
+			handleRestError(whiteboard.SlackClient, err, ev.Channel)
+			return
+		}
+		if entryType, ok = items.Find(itemId); !ok {
+			handleItemNotFound(whiteboard.SlackClient, itemId, ev.Channel)
+			return
+		}
+		entryType.GetEntry().StandupId = standup.Id
+		entryType.GetEntry().Backend = standup.Backend
+	} else if missingEntry(entryType) || len(entryType.GetEntry().Id) == 0 {
+		handleMissingEntry(whiteboard.SlackClient, ev.Channel)
+		return
+	} else {
+		standup = whiteboard.entryStandup(ev.Channel, entryType, standup)
+	}
+
+	entry := entryType.GetEntry()
+	if !whiteboard.canDelete(standup, slackUser, entry, ev) {
+		return
+	}
+
+	whiteboard.Store.SetPendingDelete(ev.Channel, slackUser.Username, PendingDelete{ItemId: entry.Id, Standup: standup})
+	whiteboard.SlackClient.PostEntry(entry, ev.Channel, "Are you sure you want to delete this entry? Confirm with `wb delete yes`\n\n")
+}
+
+// handleDeleteConfirmation deletes the item the user asked to delete in this channel a few minutes ago, from the standup
+// it was on then.
+func (whiteboard WhiteboardApp) handleDeleteConfirmation(slackUser SlackUser, entryType EntryType, ev *slack.MessageEvent) {
+	pending, ok := whiteboard.Store.GetPendingDelete(ev.Channel, slackUser.Username)
+	if !ok || len(pending.ItemId) == 0 {
+		whiteboard.SlackClient.PostReply("There's nothing waiting to be deleted. Start with `wb delete` or `wb delete <item_id>` first!", ev.Channel, THUMBS_DOWN)
+		return
+	}
+	whiteboard.Store.Delete(PendingDeleteKey(ev.Channel, slackUser.Username))
+
+	if whiteboard.deleteItem(pending.Standup, slackUser, entryType, pending.ItemId, ev.Channel) {
+		whiteboard.SlackClient.PostMessage(fmt.Sprintf("Item %v has been deleted from the whiteboard.", pending.ItemId), ev.Channel, THUMBS_UP)
+	}
+}
+
+// canDelete lets whoever created the entry with the bot delete it, going by their Slack user id, as well as workspace
+// admins and owners. Only they can delete entries created on the whiteboard itself, which have no author recorded.
+func (whiteboard WhiteboardApp) canDelete(standup Standup, slackUser SlackUser, entry *Entry, ev *slack.MessageEvent) bool {
+	author, ok := whiteboard.Store.Get(AuthorKey(standup.Backend, entry.Id))
+	if (!ok || author != ev.User) && !slackUser.IsAdmin {
+		whiteboard.SlackClient.PostReply("Only the author of an entry or a workspace admin can delete it!", ev.Channel, THUMBS_DOWN)
+		return false
+	}
+	return true
 }
 
-func (whiteboard WhiteboardApp) handleRegistrationCommand(standupId string, ev *slack.MessageEvent) {
-	standup, ok := whiteboard.RestClient.GetStandup(standupId)
+func (whiteboard WhiteboardApp) deleteItem(standup Standup, slackUser SlackUser, entryType EntryType, itemId string, channel string) bool {
+	if err := whiteboard.restClientFor(standup).Delete(NewDeleteRequest(itemId)); err != nil {
+		whiteboard.SlackClient.PostReply(fmt.Sprintf("I couldn't delete the item with id: %v. %v", itemId, RestErrorMessage(err)), channel, THUMBS_DOWN)
+		return false
+	}
+	if !missingEntry(entryType) && entryType.GetEntry().Id == itemId {
+		whiteboard.Store.Delete(EntryKey(slackUser.Username))
+	}
+	if message, ok := whiteboard.Store.GetEntryMessage(standup.Backend, itemId); ok {
+		whiteboard.SlackClient.UpdateMessage(fmt.Sprintf("Item %v has been deleted from the whiteboard.", itemId), message.Channel, message.Timestamp)
+		whiteboard.Store.Delete(EntryMessageKey(standup.Backend, itemId))
+		whiteboard.Store.Delete(ThreadKey(message.Channel, message.Timestamp))
+	}
+	whiteboard.Store.Delete(HistoryKey(standup.Backend, itemId))
+	whiteboard.Store.Delete(AuthorKey(standup.Backend, itemId))
+	return true
+}
+
+func (whiteboard WhiteboardApp) handleEditCommand(search string, ev *slack.MessageEvent, context CommandContext) {
+	standup, slackUser, _, ok := whiteboard.getEntryDetails(ev, context)
 	if !ok {
+		return
+	}
+	if len(search) == 0 {
+		whiteboard.SlackClient.PostReply("Tell me which entry to edit, like this: `wb edit 42` or `wb edit some title`", ev.Channel, THUMBS_DOWN)
+		return
+	}
+
+	items, err := whiteboard.restClientFor(standup).GetStandupItems(standup.Id)
+	if err != nil {
+		handleRestError(whiteboard.SlackClient, err, ev.Channel)
+		return
+	}
+	var found []EntryType
+	if strings.HasPrefix(search, "#") {
+		found = whiteboard.pickEditCandidate(slackUser, items, search, ev.Channel)
+	} else if entryType, ok := items.Find(search); ok {
+		found = []EntryType{entryType}
+	} else {
+		found = items.Search(search)
+	}
+
+	switch len(found) {
+	case 0:
+		whiteboard.SlackClient.PostReply(fmt.Sprintf("I couldn't find an entry matching: %v", search), ev.Channel, THUMBS_DOWN)
+	case 1:
+		entryType := found[0]
+		entry := entryType.GetEntry()
+		entry.StandupId = standup.Id
+		entry.Backend = standup.Backend
+		whiteboard.catchUpCard(standup, entry)
+		whiteboard.Store.Delete(EditCandidatesKey(ev.Channel, slackUser.Username))
+		whiteboard.Store.SetEntry(slackUser.Username, entryType)
+		whiteboard.SlackClient.PostEntry(entry, ev.Channel, THUMBS_UP + "_Now go update the details. Need help?_ `wb ?`\n\n" + strings.ToUpper(entry.ItemKind) + "\n")
+	default:
+		whiteboard.listEditCandidates(slackUser, found, ev)
+	}
+}
+
+func (whiteboard WhiteboardApp) pickEditCandidate(slackUser SlackUser, items StandupItems, pick string, channel string) (found []EntryType) {
+	candidates, ok := whiteboard.Store.Get(EditCandidatesKey(channel, slackUser.Username))
+	index, err := strconv.Atoi(strings.TrimPrefix(pick, "#"))
+	if !ok || err != nil {
+		return
+	}
+	itemIds := strings.Split(candidates, ",")
+	if index < 1 || index > len(itemIds) {
+		return
+	}
+	if entryType, ok := items.Find(itemIds[index - 1]); ok {
+		found = []EntryType{entryType}
+	}
+	return
+}
+
+func (whiteboard WhiteboardApp) listEditCandidates(slackUser SlackUser, found []EntryType, ev *slack.MessageEvent) {
+	var buffer bytes.Buffer
+	itemIds := make([]string, len(found))
+	buffer.WriteString("I found a few entries, which one do you want to edit?\n")
+	for i, entryType := range found {
+		entry := entryType.GetEntry()
+		itemIds[i] = entry.Id
+		buffer.WriteString(fmt.Sprintf("\n`#%v` *%v* [%v] %v", i + 1, entry.Title, entry.Author, entry.GetDateString()))
+	}
+	buffer.WriteString("\n\nPick one with `wb edit #1`")
+	whiteboard.Store.SetExpiring(EditCandidatesKey(ev.Channel, slackUser.Username), strings.Join(itemIds, ","), ENTRY_EXPIRY)
+	whiteboard.SlackClient.PostReply(buffer.String(), ev.Channel, "")
+}
+
+func (whiteboard WhiteboardApp) handleRegistrationCommand(input string, ev *slack.MessageEvent, _ CommandContext) {
+	input, alias := readStandupAlias(input)
+	backend, standupId := "", input
+	if keyword, rest := readNextCommand(input); len(rest) > 0 {
+		backend, standupId = keyword, rest
+	}
+	restClient, ok := whiteboard.RestClient.ForBackend(backend)
+	if !ok {
+		whiteboard.SlackClient.PostReply(fmt.Sprintf("I don't know a whiteboard called: %v", backend), ev.Channel, THUMBS_DOWN)
+		return
+	}
+
+	standup, err := restClient.GetStandup(standupId)
+	if IsNotFound(err) {
 		handleStandupNotFound(whiteboard.SlackClient, standupId, ev.Channel)
 		return
 	}
+	if err != nil {
+		handleRestError(whiteboard.SlackClient, err, ev.Channel)
+		return
+	}
+	standup.Backend = backend
+	if len(alias) > 0 {
+		whiteboard.registerNamedStandup(standup, alias, ev.Channel)
+		return
+	}
 	whiteboard.Store.SetStandup(ev.Channel, standup)
 	whiteboard.SlackClient.PostMessage(fmt.Sprintf("Standup %v has been registered! You can now start creating Whiteboard entries!", standup.Title), ev.Channel, THUMBS_UP)
 }
 
-func (whiteboard WhiteboardApp) handleUsageCommand(_ string, ev *slack.MessageEvent) {
-	whiteboard.SlackClient.PostMessageWithMarkdown(USAGE, ev.Channel, "")
+func (whiteboard WhiteboardApp) handleUsageCommand(_ string, ev *slack.MessageEvent, _ CommandContext) {
+	whiteboard.SlackClient.PostReply(USAGE, ev.Channel, "")
 }
 
-func (whiteboard WhiteboardApp) handlePresentCommand(numDays string, ev *slack.MessageEvent) {
-	standup, slackUser, _, ok := whiteboard.getEntryDetails(ev)
+func (whiteboard WhiteboardApp) handlePresentCommand(filters string, ev *slack.MessageEvent, context CommandContext) {
+	standup, _, _, ok := whiteboard.getEntryDetails(ev, context)
 	if !ok {
 		return
 	}
-	items, ok := whiteboard.RestClient.GetStandupItems(standup.Id)
-	if !ok || items.Empty() {
-		whiteboard.SlackClient.PostMessage("Hey, there's no entries in today's standup yet, why not add some?", ev.Channel, THUMBS_DOWN)
+	if keyword, order := readNextCommand(filters); keyword == SECTION_ORDER_COMMAND {
+		whiteboard.handleSectionOrder(order, ev.Channel)
+		return
+	}
+	whiteboard.presentStandup(standup, filters, ev.Channel)
+}
+
+func (whiteboard WhiteboardApp) presentStandup(standup Standup, filters string, channel string) {
+	sections := whiteboard.sectionOrder(channel)
+	var chosenSections, dates []string
+	for _, filter := range strings.Fields(filters) {
+		if section, ok := ParseSection(filter); ok {
+			chosenSections = append(chosenSections, section)
+		} else {
+			dates = append(dates, filter)
+		}
+	}
+	if len(chosenSections) > 0 {
+		sections = chosenSections
+	}
+
+	// A plain number is how many days ahead to show, anything else is a date or a range of dates.
+	numDays, dateFilter := "", strings.Join(dates, " ")
+	var dateRange *DateRange
+	if _, err := strconv.ParseUint(dateFilter, 10, 32); err == nil {
+		numDays = dateFilter
+	} else if len(dateFilter) > 0 {
+		parsedRange, err := ParseDateRange(whiteboard.Clock, dateFilter, standup.Location())
+		if err != nil {
+			whiteboard.SlackClient.PostReply(fmt.Sprintf("%v\nTry sections like `helps`, a number of days, or dates like `this week` or `2015-12-01..2015-12-05`", err), channel, THUMBS_DOWN)
+			return
+		}
+		dateRange = &parsedRange
+	} else {
+		// Without any dates, leave out what's already happened in the standup's zone.
+		upcoming := FromToday(whiteboard.Clock, standup.Location())
+		dateRange = &upcoming
+	}
+
+	items, err := whiteboard.restClientFor(standup).GetStandupItems(standup.Id)
+	if err != nil {
+		handleRestError(whiteboard.SlackClient, err, channel)
+		return
+	}
+	if items.Empty() {
+		handleNoEntries(whiteboard.SlackClient, channel)
 		return
 	}
 
 	if (len(numDays) > 0) {
 		numDaysInt, err := strconv.Atoi(numDays)
 		if err == nil {
-			items.Events = whiteboard.FilterOutOld(items.Events, numDaysInt, slackUser.TimeZone)
-			items.Faces = whiteboard.FilterOutOld(items.Faces, numDaysInt, slackUser.TimeZone)
-			items.Helps = whiteboard.FilterOutOld(items.Helps, numDaysInt, slackUser.TimeZone)
-			items.Interestings = whiteboard.FilterOutOld(items.Interestings, numDaysInt, slackUser.TimeZone)
+			items.Events = whiteboard.FilterOutOld(items.Events, numDaysInt, standup.TimeZone)
+			items.Faces = whiteboard.FilterOutOld(items.Faces, numDaysInt, standup.TimeZone)
+			items.Helps = whiteboard.FilterOutOld(items.Helps, numDaysInt, standup.TimeZone)
+			items.Interestings = whiteboard.FilterOutOld(items.Interestings, numDaysInt, standup.TimeZone)
+		}
+	}
+	if dateRange != nil {
+		items = items.FilterDates(*dateRange)
+	}
+	if !items.HasEntries(sections) {
+		whiteboard.SlackClient.PostReply("Hey, there's no entries to present for that, try `wb present` to see them all.", channel, THUMBS_DOWN)
+		return
+	}
+	whiteboard.SlackClient.PostMessage(items.Present(sections), channel, "")
+}
+
+func (whiteboard WhiteboardApp) handleSectionOrder(order string, channel string) {
+	if len(order) > 0 {
+		var sections []string
+		for _, name := range strings.Fields(order) {
+			section, ok := ParseSection(name)
+			if !ok {
+				handleUnknownSection(whiteboard.SlackClient, name, channel)
+				return
+			}
+			sections = append(sections, section)
+		}
+		for _, section := range DEFAULT_SECTION_ORDER {
+			if !containsString(sections, section) {
+				sections = append(sections, section)
+			}
 		}
+		whiteboard.Store.Set(SectionOrderKey(channel), strings.Join(sections, ","))
 	}
-	whiteboard.SlackClient.PostMessage(items.String(), ev.Channel, "")
+	whiteboard.SlackClient.PostReply(fmt.Sprintf("Standup is presented in this order: %v", strings.Join(whiteboard.sectionOrder(channel), ", ")), channel, THUMBS_UP)
 }
 
-func (whiteboard WhiteboardApp) getEntryDetails(ev *slack.MessageEvent) (standup Standup, slackUser SlackUser, entryType EntryType, ok bool) {
-	standup, ok = whiteboard.Store.GetStandup(ev.Channel)
+// sectionOrder is the order the channel presents its standup in, set with `wb present order`.
+func (whiteboard WhiteboardApp) sectionOrder(channel string) []string {
+	if order, ok := whiteboard.Store.Get(SectionOrderKey(channel)); ok && len(order) > 0 {
+		return strings.Split(order, ",")
+	}
+	return DEFAULT_SECTION_ORDER
+}
+
+func (whiteboard WhiteboardApp) getEntryDetails(ev *slack.MessageEvent, context CommandContext) (standup Standup, slackUser SlackUser, entryType EntryType, ok bool) {
+	standup, ok = whiteboard.selectedStandup(ev.Channel, context)
+	if !ok && len(context.StandupName) > 0 {
+		handleUnknownStandup(whiteboard.SlackClient, context.StandupName, ev.Channel)
+		return
+	}
 	if !ok {
 		handleNotRegistered(whiteboard.SlackClient, ev.Channel)
 		return
 	}
 
 	slackUser = whiteboard.SlackClient.GetUserDetails(ev.User)
-	entryType = whiteboard.EntryMap[slackUser.Username]
+	if context.Entry != nil {
+		entryType = context.Entry
+		return
+	}
+	entryType, _ = whiteboard.Store.GetEntry(slackUser.Username)
 	return
 }
 
-func (whiteboard WhiteboardApp) handleDefault(_ string, ev *slack.MessageEvent) {
-	_, slackUser, _, ok := whiteboard.getEntryDetails(ev)
+func (whiteboard WhiteboardApp) handleDefault(_ string, ev *slack.MessageEvent, context CommandContext) {
+	_, slackUser, _, ok := whiteboard.getEntryDetails(ev, context)
 	if !ok {
 		return
 	}
 	_, userInput := readNextCommand(getInputString(ev))
 
-	whiteboard.SlackClient.PostMessage(fmt.Sprintf("%v no you %v", slackUser.Username, userInput), ev.Channel, "")
+	whiteboard.SlackClient.PostReply(fmt.Sprintf("%v no you %v", slackUser.Username, userInput), ev.Channel, "")
 }
 
-func (whiteboard WhiteboardApp) validateAndPost(entryType EntryType, ev *slack.MessageEvent) {
-	status := ""
+func (whiteboard WhiteboardApp) validateAndPost(standup Standup, entryType EntryType, ev *slack.MessageEvent) (posted bool) {
 	entry := entryType.GetEntry()
-	if entryType.Validate() {
-		if itemId, ok := PostEntryToWhiteboard(whiteboard.RestClient, entryType); ok {
-			if len(entry.Id) == 0 {
-				status = THUMBS_UP + "_Now go update the details. Need help?_ `wb ?`\n\n" + strings.ToUpper(entry.ItemKind) + "\n"
-			} else {
-				status = THUMBS_UP + strings.ToUpper(entry.ItemKind) + "\n"
-			}
-			entry.Id = itemId
+	if !entryType.Validate() {
+		whiteboard.SlackClient.PostEntry(entry, ev.Channel, "")
+		return
+	}
+	itemId, err := PostEntryToWhiteboard(whiteboard.restClientFor(standup), entryType)
+	if err != nil {
+		whiteboard.SlackClient.PostEntry(entry, ev.Channel, THUMBS_DOWN + RestErrorMessage(err) + "\n")
+		return
+	}
+
+	status := THUMBS_UP + strings.ToUpper(entry.ItemKind) + "\n"
+	if len(entry.Id) == 0 {
+		status = THUMBS_UP + "_Now go update the details. Need help?_ `wb ?`\n\n" + strings.ToUpper(entry.ItemKind) + "\n"
+		whiteboard.Store.Set(AuthorKey(standup.Backend, itemId), ev.User)
+	}
+	entry.Id = itemId
+	whiteboard.postEntryCard(standup, entry, status, ev.Channel)
+	return true
+}
+
+// postEntryCard updates the entry's card in place once it has been posted to the channel, rather than posting a new one.
+func (whiteboard WhiteboardApp) postEntryCard(standup Standup, entry *Entry, status string, channel string) {
+	entry.Backend = standup.Backend
+	if len(entry.Id) > 0 {
+		if message, ok := whiteboard.Store.GetEntryMessage(standup.Backend, entry.Id); ok && message.Channel == channel {
+			whiteboard.SlackClient.UpdateEntry(entry, message.Channel, message.Timestamp, status)
+			whiteboard.rememberEntryMessage(standup.Backend, entry, message)
+			return
 		}
 	}
-	whiteboard.SlackClient.PostEntry(entry, ev.Channel, status)
+	timestamp := whiteboard.SlackClient.PostEntry(entry, channel, status)
+	if len(entry.Id) > 0 && len(timestamp) > 0 {
+		whiteboard.rememberEntryMessage(standup.Backend, entry, EntryMessage{Channel: channel, Timestamp: timestamp})
+	}
+}
+
+// rememberEntryMessage keeps where the entry's card is and what it shows, so it can be updated in place and replies in
+// its thread can find the entry, for ENTRY_EXPIRY after the entry was last changed.
+func (whiteboard WhiteboardApp) rememberEntryMessage(backend string, entry *Entry, message EntryMessage) {
+	fields := FieldsOf(entry)
+	message.Fields = &fields
+	whiteboard.Store.SetEntryMessage(backend, entry.Id, message)
+	whiteboard.Store.SetExpiring(ThreadKey(message.Channel, message.Timestamp), EntryRef{Backend: backend, ItemId: entry.Id}.String(), ENTRY_EXPIRY)
+}
+
+// restClientFor is the client for the standup's whiteboard. When that whiteboard has been taken out of the config,
+// every request fails with an UNKNOWN_BACKEND_ERROR rather than going to the default one.
+func (whiteboard WhiteboardApp) restClientFor(standup Standup) RestClient {
+	restClient, ok := whiteboard.RestClient.ForBackend(standup.Backend)
+	if !ok {
+		return unknownBackendClient{RestClient: restClient, name: standup.Backend}
+	}
+	return restClient
+}
+
+func (whiteboard WhiteboardApp) handleAmbiguousCommand(command string, candidates []string, ev *slack.MessageEvent) {
+	whiteboard.SlackClient.PostReply(fmt.Sprintf("Not sure what `%v` means, did you mean: %v?", command, strings.Join(candidates, ", ")), ev.Channel, THUMBS_DOWN)
 }
 
 func (whiteboard WhiteboardApp) handleMissingTitle(channel string) {
-	whiteboard.SlackClient.PostMessageWithMarkdown("Hey, next time add a title along with your entry!\nLike this: `wb i My title`\nNeed help? Try `wb ?`", channel, THUMBS_DOWN)
+	whiteboard.SlackClient.PostReply("Hey, next time add a title along with your entry!\nLike this: `wb i My title`\nNeed help? Try `wb ?`", channel, THUMBS_DOWN)
 }
 
 func getInputString(ev *slack.MessageEvent) string {
-	if ev.Upload {
-		return ev.File.Title
+	if file, ok := uploadedFile(ev); ok {
+		return file.Title
 	} else {
 		return ev.Text
 	}
 }
 
-func (whiteboard WhiteboardApp) FilterOutOld(entries []Entry, numDays int, userTimeZone string) []Entry {
-
-	location, err := time.LoadLocation(userTimeZone)
-	if err != nil {
-		location = time.Local
+// uploadedFile is the file shared with the message, whose title holds the command.
+func uploadedFile(ev *slack.MessageEvent) (file slack.File, ok bool) {
+	if !ev.Upload || len(ev.Files) == 0 {
+		return
 	}
+	return ev.Files[0], true
+}
 
-	entiriesFiltered := make([]Entry, 0)
-	for _, entry := range entries {
-		entryDate, err := time.Parse("2006-01-02", entry.Date)
-		cutOff := whiteboard.Clock.Now().In(location).AddDate(0, 0, numDays)
-		if err != nil || entryDate.In(location).Before(cutOff) || entryDate.In(location).Equal(cutOff) {
-			entiriesFiltered = append(entiriesFiltered, entry)
-		}
-	}
-	return entiriesFiltered
+// FilterOutOld keeps the entries dated from today up to numDays days ahead in the standup's time zone, along with
+// entries whose date can't be read.
+func (whiteboard WhiteboardApp) FilterOutOld(entries []Entry, numDays int, standupTimeZone string) []Entry {
+	return NextDays(whiteboard.Clock, numDays, Standup{TimeZone: standupTimeZone}.Location()).Filter(entries)
 }
 
 
@@ -0,0 +1,97 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"html"
+	"io/ioutil"
+	"net/http"
+	"net/http/cookiejar"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	metaTagPattern     = regexp.MustCompile(`<meta[^>]*>`)
+	metaContentPattern = regexp.MustCompile(`content="([^"]*)"`)
+)
+
+// WhiteboardSession keeps the Rails session cookie and the CSRF token that goes with it, so requests to a Whiteboard
+// don't need a token copied by hand.
+type WhiteboardSession struct {
+	HostUrl    string
+	httpClient *http.Client
+	mutex      sync.Mutex
+	token      string
+}
+
+func NewWhiteboardSession(hostUrl string, token string) *WhiteboardSession {
+	jar, _ := cookiejar.New(nil)
+	return &WhiteboardSession{HostUrl: hostUrl, token: token, httpClient: &http.Client{Jar: jar, CheckRedirect: noRedirect}}
+}
+
+// Token returns the current CSRF token, fetching one first if there isn't one yet.
+func (session *WhiteboardSession) Token(timeout time.Duration) (token string, err error) {
+	session.mutex.Lock()
+	token = session.token
+	session.mutex.Unlock()
+	if len(token) > 0 {
+		return
+	}
+	return session.Refresh(timeout)
+}
+
+// Refresh loads a Whiteboard page to start a new session and scrapes its CSRF token.
+func (session *WhiteboardSession) Refresh(timeout time.Duration) (token string, err error) {
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	httpRequest, err := http.NewRequest("GET", session.HostUrl + "/", nil)
+	if err != nil {
+		return "", &RestError{Kind: NETWORK_ERROR, Err: err}
+	}
+	httpRequest.Header.Add("Accept", "text/html")
+	resp, err := session.httpClient.Do(httpRequest.WithContext(ctx))
+	if err != nil {
+		return "", &RestError{Kind: NETWORK_ERROR, Err: err}
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", NewStatusError(resp.StatusCode)
+	}
+	page, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return "", &RestError{Kind: NETWORK_ERROR, Err: err}
+	}
+
+	if token = ScrapeCsrfToken(string(page)); len(token) == 0 {
+		return "", &RestError{Kind: AUTH_ERROR, StatusCode: resp.StatusCode, Err: errors.New("no csrf-token meta tag on the page")}
+	}
+	session.token = token
+	return
+}
+
+func ScrapeCsrfToken(page string) string {
+	for _, tag := range metaTagPattern.FindAllString(page, -1) {
+		if !strings.Contains(tag, `name="csrf-token"`) {
+			continue
+		}
+		if content := metaContentPattern.FindStringSubmatch(tag); content != nil {
+			return html.UnescapeString(content[1])
+		}
+	}
+	return ""
+}
+
+// sessionExpired reports whether the Whiteboard turned a request away because of a stale token or session.
+func sessionExpired(response whiteboardResponse) bool {
+	if response.statusCode == http.StatusUnprocessableEntity {
+		return true
+	}
+	location := response.header.Get("Location")
+	return response.statusCode == http.StatusFound && (strings.Contains(location, "/login") || strings.Contains(location, "/sign_in"))
+}
@@ -0,0 +1,149 @@
+package app_test
+
+import (
+	"encoding/json"
+	"fmt"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/config"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"time"
+)
+
+// fakeWhiteboard mimics the Rails app: a page load starts a session with its own CSRF token, and items can only be
+// posted with the cookie and token of the current session.
+type fakeWhiteboard struct {
+	mutex     sync.Mutex
+	session   int
+	pageLoads int
+	rejectAll bool
+	loggedOut bool
+}
+
+func (whiteboard *fakeWhiteboard) ServeHTTP(responseWriter http.ResponseWriter, req *http.Request) {
+	whiteboard.mutex.Lock()
+	defer whiteboard.mutex.Unlock()
+
+	if req.URL.Path == "/" {
+		whiteboard.session++
+		whiteboard.pageLoads++
+		http.SetCookie(responseWriter, &http.Cookie{Name: "_whiteboard_session", Value: fmt.Sprint(whiteboard.session)})
+		fmt.Fprintf(responseWriter, `<html><head><meta name="csrf-param" content="authenticity_token" />
+<meta name="csrf-token" content="token-%v" /></head></html>`, whiteboard.session)
+		return
+	}
+
+	cookie, err := req.Cookie("_whiteboard_session")
+	if err != nil || cookie.Value != fmt.Sprint(whiteboard.session) || whiteboard.loggedOut {
+		http.Redirect(responseWriter, req, "/login", http.StatusFound)
+		return
+	}
+	if req.Method == "GET" {
+		responseWriter.Write([]byte(`{"id": 1, "title": "Sydney"}`))
+		return
+	}
+
+	var request model.WhiteboardRequest
+	json.NewDecoder(req.Body).Decode(&request)
+	if whiteboard.rejectAll || request.Token != fmt.Sprintf("token-%v", whiteboard.session) {
+		responseWriter.WriteHeader(http.StatusUnprocessableEntity)
+		return
+	}
+	responseWriter.Header().Set("Item-Id", "42")
+	http.Redirect(responseWriter, req, "/standups/1", http.StatusFound)
+}
+
+func (whiteboard *fakeWhiteboard) expireSession() {
+	whiteboard.mutex.Lock()
+	defer whiteboard.mutex.Unlock()
+	whiteboard.session++
+}
+
+var _ = Describe("Whiteboard Session", func() {
+
+	var (
+		fake   *fakeWhiteboard
+		server *httptest.Server
+		client RealRestClient
+	)
+
+	newItem := model.WhiteboardRequest{Item: model.Item{StandupId: 1, Title: "Something"}}
+	updateItem := model.WhiteboardRequest{Method: "patch", Id: "42", Item: model.Item{StandupId: 1, Title: "Something else"}}
+
+	BeforeEach(func() {
+		fake = &fakeWhiteboard{}
+		server = httptest.NewServer(fake)
+		client = NewRestClient(config.Config{DefaultBackend: "sydney", Backends: map[string]config.Backend{
+			"sydney": config.Backend{Name: "sydney", HostUrl: server.URL},
+		}})
+		client.Timeout = time.Second
+		client.RetryBackoff = time.Millisecond
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	It("should scrape a token before the first post", func() {
+		itemId, err := client.Post(newItem)
+		Expect(err).To(BeNil())
+		Expect(itemId).To(Equal("42"))
+		Expect(fake.pageLoads).To(Equal(1))
+	})
+
+	It("should share the token between creating and updating items", func() {
+		client.Post(newItem)
+		_, err := client.Post(updateItem)
+		Expect(err).To(BeNil())
+		Expect(fake.pageLoads).To(Equal(1))
+	})
+
+	It("should log in again when the token is rejected", func() {
+		client.Post(newItem)
+		fake.expireSession()
+		itemId, err := client.Post(updateItem)
+		Expect(err).To(BeNil())
+		Expect(itemId).To(Equal("42"))
+		Expect(fake.pageLoads).To(Equal(2))
+	})
+
+	It("should log in again when redirected to the login page", func() {
+		standup, err := client.GetStandup("1")
+		Expect(err).To(BeNil())
+		Expect(standup.Title).To(Equal("Sydney"))
+		Expect(fake.pageLoads).To(Equal(1))
+	})
+
+	It("should only log in again once", func() {
+		fake.rejectAll = true
+		_, err := client.Post(newItem)
+		Expect(err.(*RestError).Kind).To(Equal(AUTH_ERROR))
+		Expect(fake.pageLoads).To(Equal(2))
+	})
+
+	It("should report an auth error when it keeps being redirected to the login page", func() {
+		fake.loggedOut = true
+		_, err := client.Post(newItem)
+		Expect(err.(*RestError).Kind).To(Equal(AUTH_ERROR))
+		_, err = client.Post(updateItem)
+		Expect(err.(*RestError).Kind).To(Equal(AUTH_ERROR))
+		err = client.Delete(model.NewDeleteRequest("42"))
+		Expect(err.(*RestError).Kind).To(Equal(AUTH_ERROR))
+		_, err = client.CreatePost(model.NewPostRequest(1, "Sydney 2015-01-02"))
+		Expect(err.(*RestError).Kind).To(Equal(AUTH_ERROR))
+		err = client.SendPostEmail(model.NewSendEmailRequest("7"))
+		Expect(err.(*RestError).Kind).To(Equal(AUTH_ERROR))
+		_, err = client.GetStandup("1")
+		Expect(err.(*RestError).Kind).To(Equal(AUTH_ERROR))
+	})
+
+	It("should find the token whatever the order of the attributes", func() {
+		Expect(ScrapeCsrfToken(`<meta content="abc+/=" name="csrf-token">`)).To(Equal("abc+/="))
+		Expect(ScrapeCsrfToken(`<meta name="csrf-token" content="a&amp;b" />`)).To(Equal("a&b"))
+		Expect(ScrapeCsrfToken(`<meta name="csrf-param" content="authenticity_token" />`)).To(BeEmpty())
+	})
+})
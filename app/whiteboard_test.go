@@ -6,6 +6,7 @@ import (
 	"github.com/pivotal-sydney/whiteboardbot/model"
 	"github.com/pivotal-sydney/whiteboardbot/app"
 	"github.com/pivotal-sydney/whiteboardbot/spec"
+	"time"
 )
 
 var _ = Describe("Whiteboard", func() {
@@ -34,6 +35,44 @@ var _ = Describe("Whiteboard", func() {
 			Expect(filteredEntries[0]).To(Equal(entries[0]))
 			Expect(filteredEntries[1]).To(Equal(entries[1]))
 		})
+		It("should leave out entries from before today", func() {
+			entries := []model.Entry{
+				model.Entry{Title: "yesterday", Date: "2015-01-01", Author: "Andrew"},
+				model.Entry{Title: "today", Date: "2015-01-02", Author: "Andrew"}}
+
+			filteredEntries := whiteboard.FilterOutOld(entries, 5, "Australia/Sydney")
+
+			Expect(filteredEntries).To(Equal(entries[1:]))
+		})
+
+		It("should use today's date west of UTC", func() {
+			entries := []model.Entry{
+				model.Entry{Title: "two days ago", Date: "2014-12-31", Author: "Andrew"},
+				model.Entry{Title: "today in New York", Date: "2015-01-01", Author: "Andrew"},
+				model.Entry{Title: "in five days", Date: "2015-01-06", Author: "Andrew"},
+				model.Entry{Title: "in six days", Date: "2015-01-07", Author: "Dariusz"}}
+
+			filteredEntries := whiteboard.FilterOutOld(entries, 5, "America/New_York")
+
+			Expect(filteredEntries).To(Equal(entries[1:3]))
+		})
+
+		It("should compare dates rather than times of day", func() {
+			clock := &spec.FakeClock{CurrentTime: time.Date(2015, 1, 2, 23, 30, 0, 0, time.UTC)}
+			whiteboard.Clock = clock
+			entries := []model.Entry{
+				model.Entry{Title: "yesterday in Sydney", Date: "2015-01-02", Author: "Andrew"},
+				model.Entry{Title: "today in Sydney", Date: "2015-01-03", Author: "Andrew"},
+				model.Entry{Title: "tomorrow in Sydney", Date: "2015-01-04", Author: "Andrew"}}
+
+			Expect(whiteboard.FilterOutOld(entries, 1, "Australia/Sydney")).To(Equal(entries[1:]))
+			Expect(whiteboard.FilterOutOld(entries, 0, "America/New_York")).To(Equal(entries[:1]))
+
+			clock.CurrentTime = time.Date(2015, 1, 2, 0, 30, 0, 0, time.UTC)
+			Expect(whiteboard.FilterOutOld(entries, 0, "Australia/Sydney")).To(Equal(entries[:1]))
+			Expect(whiteboard.FilterOutOld(entries, 0, "America/New_York")).To(BeEmpty())
+		})
+
 		It("should still return entries within invalid dates", func() {
 			entries := []model.Entry{
 				model.Entry{Title: "empty date", Date: "", Author: "Andrew"},
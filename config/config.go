@@ -0,0 +1,99 @@
+package config
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+)
+
+const (
+	DEFAULT_BACKEND = "default"
+)
+
+type Config struct {
+	DefaultBackend string             `json:"default_backend"`
+	Backends       map[string]Backend `json:"backends"`
+}
+
+type Backend struct {
+	Name      string `json:"-"`
+	HostUrl   string `json:"host_url"`
+	AuthToken string `json:"auth_token"`
+}
+
+// Load reads the config file named by WB_CONFIG_FILE, if any, then applies environment overrides. WB_HOST_URL and
+// WB_AUTH_TOKEN apply to the default backend, and WB_<NAME>_HOST_URL and WB_<NAME>_AUTH_TOKEN to the named one.
+func Load() (config Config, err error) {
+	if path := os.Getenv("WB_CONFIG_FILE"); len(path) > 0 {
+		if config, err = ReadFile(path); err != nil {
+			return
+		}
+	}
+	config.ApplyEnv()
+	return
+}
+
+func ReadFile(path string) (config Config, err error) {
+	configJson, err := ioutil.ReadFile(path)
+	if err != nil {
+		return
+	}
+	err = json.Unmarshal(configJson, &config)
+	return
+}
+
+func (config *Config) ApplyEnv() {
+	if config.Backends == nil {
+		config.Backends = make(map[string]Backend)
+	}
+	if len(config.DefaultBackend) == 0 {
+		config.DefaultBackend = DEFAULT_BACKEND
+	}
+
+	backends := make(map[string]Backend)
+	for name, backend := range config.Backends {
+		backends[strings.ToLower(name)] = backend
+	}
+	config.DefaultBackend = strings.ToLower(config.DefaultBackend)
+	if _, ok := backends[config.DefaultBackend]; !ok {
+		backends[config.DefaultBackend] = Backend{}
+	}
+
+	for name, backend := range backends {
+		prefix := "WB_" + strings.ToUpper(name) + "_"
+		override(&backend.HostUrl, prefix + "HOST_URL")
+		override(&backend.AuthToken, prefix + "AUTH_TOKEN")
+		if name == config.DefaultBackend {
+			override(&backend.HostUrl, "WB_HOST_URL")
+			override(&backend.AuthToken, "WB_AUTH_TOKEN")
+		}
+		backend.Name = name
+		backends[name] = backend
+	}
+	config.Backends = backends
+}
+
+// GetBackend returns the named backend, or the default backend when no name is given.
+func (config Config) GetBackend(name string) (backend Backend, ok bool) {
+	if len(name) == 0 {
+		name = config.DefaultBackend
+	}
+	backend, ok = config.Backends[strings.ToLower(name)]
+	return
+}
+
+func (config Config) BackendNames() (names []string) {
+	for name := range config.Backends {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return
+}
+
+func override(value *string, key string) {
+	if envValue := os.Getenv(key); len(envValue) > 0 {
+		*value = envValue
+	}
+}
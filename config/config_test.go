@@ -0,0 +1,87 @@
+package config_test
+
+import (
+	"io/ioutil"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/config"
+	"os"
+)
+
+var _ = Describe("Config", func() {
+
+	var path string
+
+	BeforeEach(func() {
+		file, _ := ioutil.TempFile("", "whiteboardbot")
+		file.WriteString(`{
+			"default_backend": "Sydney",
+			"backends": {
+				"Sydney": {"host_url": "http://sydney.example.com", "auth_token": "sydney-token"},
+				"singapore": {"host_url": "http://singapore.example.com", "auth_token": "singapore-token"}
+			}
+		}`)
+		file.Close()
+		path = file.Name()
+	})
+
+	AfterEach(func() {
+		os.Remove(path)
+		os.Unsetenv("WB_CONFIG_FILE")
+		os.Unsetenv("WB_HOST_URL")
+		os.Unsetenv("WB_AUTH_TOKEN")
+		os.Unsetenv("WB_SINGAPORE_AUTH_TOKEN")
+	})
+
+	Context("without a config file", func() {
+		It("should configure the default backend from the environment", func() {
+			os.Setenv("WB_HOST_URL", "http://localhost:3000")
+			os.Setenv("WB_AUTH_TOKEN", "token")
+			config, err := Load()
+			Expect(err).To(BeNil())
+			backend, ok := config.GetBackend("")
+			Expect(ok).To(BeTrue())
+			Expect(backend).To(Equal(Backend{Name: DEFAULT_BACKEND, HostUrl: "http://localhost:3000", AuthToken: "token"}))
+		})
+	})
+
+	Context("with a config file", func() {
+		BeforeEach(func() {
+			os.Setenv("WB_CONFIG_FILE", path)
+		})
+
+		It("should read every backend", func() {
+			config, err := Load()
+			Expect(err).To(BeNil())
+			Expect(config.BackendNames()).To(Equal([]string{"singapore", "sydney"}))
+			backend, _ := config.GetBackend("Singapore")
+			Expect(backend.HostUrl).To(Equal("http://singapore.example.com"))
+			Expect(backend.AuthToken).To(Equal("singapore-token"))
+		})
+
+		It("should let the environment override the file", func() {
+			os.Setenv("WB_AUTH_TOKEN", "default-token")
+			os.Setenv("WB_SINGAPORE_AUTH_TOKEN", "other-token")
+			config, _ := Load()
+			backend, _ := config.GetBackend("")
+			Expect(backend.Name).To(Equal("sydney"))
+			Expect(backend.AuthToken).To(Equal("default-token"))
+			backend, _ = config.GetBackend("singapore")
+			Expect(backend.AuthToken).To(Equal("other-token"))
+		})
+
+		It("should not find an unknown backend", func() {
+			config, _ := Load()
+			_, ok := config.GetBackend("melbourne")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("with a missing config file", func() {
+		It("should fail to load", func() {
+			os.Setenv("WB_CONFIG_FILE", path + ".missing")
+			_, err := Load()
+			Expect(err).NotTo(BeNil())
+		})
+	})
+})
@@ -0,0 +1,26 @@
+module github.com/pivotal-sydney/whiteboardbot
+
+go 1.23.0
+
+require (
+	github.com/alicebob/miniredis/v2 v2.37.0
+	github.com/garyburd/redigo v1.6.0
+	github.com/nlopes/slack v0.6.0
+	github.com/onsi/ginkgo v1.16.5
+	github.com/onsi/gomega v1.20.2
+	go.etcd.io/bbolt v1.4.3
+)
+
+require (
+	github.com/fsnotify/fsnotify v1.4.9 // indirect
+	github.com/google/go-cmp v0.5.8 // indirect
+	github.com/gorilla/websocket v1.2.0 // indirect
+	github.com/nxadm/tail v1.4.8 // indirect
+	github.com/pkg/errors v0.8.0 // indirect
+	github.com/yuin/gopher-lua v1.1.1 // indirect
+	golang.org/x/net v0.20.0 // indirect
+	golang.org/x/sys v0.32.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	gopkg.in/tomb.v1 v1.0.0-20141024135613-dd632973f1e7 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
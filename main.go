@@ -2,9 +2,12 @@ package main
 
 import (
 	"fmt"
+	"github.com/garyburd/redigo/redis"
 	"github.com/nlopes/slack"
 	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/config"
 	"github.com/pivotal-sydney/whiteboardbot/model"
+	"github.com/pivotal-sydney/whiteboardbot/scheduler"
 	"net/http"
 	"os"
 	"os/signal"
@@ -13,9 +16,13 @@ import (
 
 const (
 	DEFAULT_PORT = "9000"
+	DEFAULT_SLACK_MODE = "rtm"
+	DEFAULT_STORE = "redis"
+	DEFAULT_DB_PATH = "whiteboardbot.db"
 )
 
-var redisConnectionPool = NewPool()
+var redisConnectionPool *redis.Pool
+var boltStore *BoltStore
 
 func init() {
 	shutdownChannel := make(chan os.Signal, 1)
@@ -31,12 +38,39 @@ func init() {
 func main() {
 	api := slack.New(os.Getenv("WB_BOT_API_TOKEN"))
 	rtm := api.NewRTM()
-	go rtm.ManageConnection()
 
-	store := RealStore{redisConnectionPool}
-	slackClient := Slack{SlackRtm: rtm}
-	whiteboard := NewWhiteboard(&slackClient, &RealRestClient{}, model.RealClock{}, &store)
+	storeType := getStoreType()
+	store, err := newStore(storeType)
+	if err != nil {
+		fmt.Printf("Error setting up the %v store: %v\n", storeType, err)
+		os.Exit(1)
+	}
+	slackClient := Slack{SlackRtm: rtm, Token: os.Getenv("WB_BOT_API_TOKEN")}
+	wbConfig, err := config.Load()
+	if err != nil {
+		fmt.Printf("Error loading config: %v\n", err)
+		os.Exit(1)
+	}
+	restClient := NewRestClient(wbConfig)
+	whiteboard := NewWhiteboard(&slackClient, &restClient, model.RealClock{}, store)
+	dispatcher := NewDispatcher(whiteboard.ParseMessageEvent)
+	dispatcher.Key = whiteboard.DispatchKey
+	go scheduler.NewScheduler(whiteboard).Run(make(chan struct{}))
+
+	signingSecret := os.Getenv("WB_SLACK_SIGNING_SECRET")
+	http.Handle("/slack/commands", SlashCommandHandler{SigningSecret: signingSecret, Clock: model.RealClock{}, Whiteboard: whiteboard, Dispatcher: dispatcher})
+	http.Handle("/slack/interactivity", InteractivityHandler{SigningSecret: signingSecret, Clock: model.RealClock{}, Whiteboard: whiteboard, Dispatcher: dispatcher})
+
+	mode := getSlackMode()
+	if mode == "events" || mode == "both" {
+		http.Handle("/slack/events", EventsHandler{SigningSecret: signingSecret, Clock: model.RealClock{}, Handle: dispatcher.Dispatch})
+	}
+	if mode == "events" {
+		startHttpServer()
+		return
+	}
 
+	go rtm.ManageConnection()
 	go startHttpServer()
 
 	Loop:
@@ -45,7 +79,7 @@ func main() {
 		case msg := <-rtm.IncomingEvents:
 			switch ev := msg.Data.(type) {
 			case *slack.MessageEvent:
-				go whiteboard.ParseMessageEvent(ev)
+				dispatcher.Dispatch(ev)
 			case *slack.InvalidAuthEvent:
 				fmt.Println("Invalid credentials")
 				break Loop
@@ -55,11 +89,54 @@ func main() {
 	}
 }
 
+// newStore sets up the store picked by WB_DB_TYPE, migrating Redis or BoltDB to the current schema first.
+func newStore(storeType string) (store Store, err error) {
+	switch storeType {
+	case "memory":
+		return NewMemoryStore(model.RealClock{}), nil
+	case "bolt":
+		if boltStore, err = NewBoltStore(getDbPath(), model.RealClock{}); err != nil {
+			return nil, err
+		}
+		if err = migrate(boltStore, "BoltDB"); err != nil {
+			return nil, err
+		}
+		return boltStore, nil
+	}
+
+	redisConnectionPool = NewPool()
+	redisStore := NewRealStore(redisConnectionPool)
+	if err = migrate(redisStore, "Redis"); err != nil {
+		return nil, err
+	}
+	return redisStore, nil
+}
+
+// migratingStore is a store that can hold data from earlier versions of the bot.
+type migratingStore interface {
+	Migrate() (migrated int, err error)
+}
+
+func migrate(store migratingStore, name string) error {
+	migrated, err := store.Migrate()
+	if err != nil {
+		return fmt.Errorf("couldn't migrate %v to schema v%v: %v", name, SCHEMA_VERSION, err)
+	}
+	if migrated > 0 {
+		fmt.Printf("Migrated %v keys to schema v%v\n", migrated, SCHEMA_VERSION)
+	}
+	return nil
+}
+
 func cleanup() {
 	if redisConnectionPool != nil {
 		fmt.Println("Closing Redis connection pool")
 		redisConnectionPool.Close()
 	}
+	if boltStore != nil {
+		fmt.Println("Closing BoltDB file")
+		boltStore.Close()
+	}
 }
 
 func startHttpServer() {
@@ -69,6 +146,34 @@ func startHttpServer() {
 	}
 }
 
+func getSlackMode() (mode string) {
+	switch mode = os.Getenv("WB_SLACK_MODE"); mode {
+	case "rtm", "events", "both":
+	default:
+		fmt.Printf("Warning, WB_SLACK_MODE not set to rtm, events or both. Defaulting to %+v\n", DEFAULT_SLACK_MODE)
+		mode = DEFAULT_SLACK_MODE
+	}
+	return
+}
+
+func getStoreType() (storeType string) {
+	switch storeType = os.Getenv("WB_DB_TYPE"); storeType {
+	case "redis", "bolt", "memory":
+	default:
+		fmt.Printf("Warning, WB_DB_TYPE not set to redis, bolt or memory. Defaulting to %+v\n", DEFAULT_STORE)
+		storeType = DEFAULT_STORE
+	}
+	return
+}
+
+func getDbPath() (path string) {
+	if path = os.Getenv("WB_DB_PATH"); len(path) == 0 {
+		fmt.Printf("Warning, WB_DB_PATH not set. Defaulting to %+v\n", DEFAULT_DB_PATH)
+		path = DEFAULT_DB_PATH
+	}
+	return
+}
+
 func getHealthCheckPort() (port string) {
 	if port = os.Getenv("PORT"); len(port) == 0 {
 		fmt.Printf("Warning, PORT not set. Defaulting to %+v\n", DEFAULT_PORT)
@@ -0,0 +1,156 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	RANGE_SEPARATOR = ".."
+)
+
+var (
+	dayMonthFormats = []string{"2 Jan", "2 January", "Jan 2", "January 2"}
+	fullDateFormats = []string{DATE_FORMAT, "2 Jan 2006", "2 January 2006", "Jan 2 2006", "January 2 2006"}
+)
+
+// DateRange runs from the start of From to the end of To, both dates at midnight in the same location. A zero To
+// leaves the range open-ended.
+type DateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// ParseDate resolves dates like `today`, `tomorrow`, `next friday`, `+3`, `3 Dec` and `2015-12-03` to midnight in
+// the location.
+func ParseDate(clock Clock, input string, location *time.Location) (date time.Time, err error) {
+	input = strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	today := midnight(clock.Now().In(location))
+
+	switch {
+	case input == "today":
+		return today, nil
+	case input == "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case input == "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	case strings.HasPrefix(input, "+") || strings.HasPrefix(input, "-"):
+		days, err := strconv.Atoi(input)
+		if err != nil {
+			return date, fmt.Errorf("%v isn't a number of days", input)
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+
+	if weekday, ok := parseWeekday(strings.TrimPrefix(strings.TrimPrefix(input, "next "), "last ")); ok {
+		days := (int(weekday) - int(today.Weekday()) + 7) % 7
+		switch {
+		case strings.HasPrefix(input, "next ") && days == 0:
+			days = 7
+		case strings.HasPrefix(input, "last "):
+			days -= 7
+			if days == 0 {
+				days = -7
+			}
+		}
+		return today.AddDate(0, 0, days), nil
+	}
+
+	for _, format := range fullDateFormats {
+		if parsed, err := time.ParseInLocation(format, input, location); err == nil {
+			return parsed, nil
+		}
+	}
+	for _, format := range dayMonthFormats {
+		if parsed, err := time.ParseInLocation(format, input, location); err == nil {
+			return time.Date(today.Year(), parsed.Month(), parsed.Day(), 0, 0, 0, 0, location), nil
+		}
+	}
+	return date, fmt.Errorf("I don't understand the date: %v", input)
+}
+
+// ParseDateRange reads `this week`, `next week`, two dates like `2015-12-01..2015-12-05`, or a single date.
+func ParseDateRange(clock Clock, input string, location *time.Location) (dateRange DateRange, err error) {
+	input = strings.Join(strings.Fields(strings.ToLower(input)), " ")
+	today := midnight(clock.Now().In(location))
+	monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+
+	switch input {
+	case "this week":
+		return DateRange{From: monday, To: monday.AddDate(0, 0, 6)}, nil
+	case "next week":
+		return DateRange{From: monday.AddDate(0, 0, 7), To: monday.AddDate(0, 0, 13)}, nil
+	}
+
+	if bounds := strings.SplitN(input, RANGE_SEPARATOR, 2); len(bounds) == 2 {
+		if dateRange.From, err = ParseDate(clock, bounds[0], location); err != nil {
+			return
+		}
+		if dateRange.To, err = ParseDate(clock, bounds[1], location); err != nil {
+			return
+		}
+		if dateRange.To.Before(dateRange.From) {
+			err = errors.New("The range has to start before it ends")
+		}
+		return
+	}
+
+	date, err := ParseDate(clock, input, location)
+	return DateRange{From: date, To: date}, err
+}
+
+// NextDays runs from today until numDays days from today.
+func NextDays(clock Clock, numDays int, location *time.Location) DateRange {
+	today := midnight(clock.Now().In(location))
+	return DateRange{From: today, To: today.AddDate(0, 0, numDays)}
+}
+
+// FromToday runs from today with no end, for leaving out entries that have already happened.
+func FromToday(clock Clock, location *time.Location) DateRange {
+	return DateRange{From: midnight(clock.Now().In(location))}
+}
+
+// Filter keeps the entries dated within the range, along with any entries whose date can't be read.
+func (dateRange DateRange) Filter(entries []Entry) []Entry {
+	filtered := make([]Entry, 0)
+	for _, entry := range entries {
+		if _, err := time.Parse(DATE_FORMAT, entry.Date); err != nil || dateRange.Contains(entry.Date) {
+			filtered = append(filtered, entry)
+		}
+	}
+	return filtered
+}
+
+// Contains reports whether a `YYYY-MM-DD` date falls in the range.
+func (dateRange DateRange) Contains(date string) bool {
+	return date >= dateRange.From.Format(DATE_FORMAT) && (dateRange.To.IsZero() || date <= dateRange.To.Format(DATE_FORMAT))
+}
+
+func (dateRange DateRange) String() string {
+	if dateRange.To.IsZero() {
+		return fmt.Sprintf("from %v", dateRange.From.Format(DATE_STRING_FORMAT))
+	}
+	if dateRange.From.Equal(dateRange.To) {
+		return dateRange.From.Format(DATE_STRING_FORMAT)
+	}
+	return fmt.Sprintf("%v to %v", dateRange.From.Format(DATE_STRING_FORMAT), dateRange.To.Format(DATE_STRING_FORMAT))
+}
+
+func parseWeekday(name string) (weekday time.Weekday, ok bool) {
+	if len(name) < 3 {
+		return
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.HasPrefix(strings.ToLower(day.String()), name) {
+			return day, true
+		}
+	}
+	return
+}
+
+func midnight(moment time.Time) time.Time {
+	return time.Date(moment.Year(), moment.Month(), moment.Day(), 0, 0, 0, 0, moment.Location())
+}
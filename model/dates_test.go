@@ -0,0 +1,119 @@
+package model_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"github.com/pivotal-sydney/whiteboardbot/spec"
+	"time"
+)
+
+var _ = Describe("Dates", func() {
+
+	// spec.MockClock is midnight UTC on Friday 2 Jan 2015, which is Friday morning in Sydney and Thursday evening
+	// in New York.
+	var (
+		clock   spec.MockClock
+		sydney  *time.Location
+		newYork *time.Location
+	)
+
+	BeforeEach(func() {
+		clock = spec.MockClock{}
+		sydney, _ = time.LoadLocation("Australia/Sydney")
+		newYork, _ = time.LoadLocation("America/New_York")
+	})
+
+	Describe("parsing a date", func() {
+		dates := []struct {
+			input    string
+			timeZone string
+			expected string
+		}{
+			{"today", "Australia/Sydney", "2015-01-02"},
+			{"today", "America/New_York", "2015-01-01"},
+			{"Tomorrow", "Australia/Sydney", "2015-01-03"},
+			{"yesterday", "Australia/Sydney", "2015-01-01"},
+			{"+3", "Australia/Sydney", "2015-01-05"},
+			{"-2", "America/New_York", "2014-12-30"},
+			{"friday", "Australia/Sydney", "2015-01-02"},
+			{"next friday", "Australia/Sydney", "2015-01-09"},
+			{"next  Fri", "America/New_York", "2015-01-02"},
+			{"last friday", "Australia/Sydney", "2014-12-26"},
+			{"monday", "Australia/Sydney", "2015-01-05"},
+			{"3 Dec", "Australia/Sydney", "2015-12-03"},
+			{"Dec 3", "America/New_York", "2015-12-03"},
+			{"3 december 2014", "Australia/Sydney", "2014-12-03"},
+			{"2015-12-03", "Australia/Sydney", "2015-12-03"},
+		}
+
+		for _, date := range dates {
+			date := date
+			It("should read "+date.input+" in "+date.timeZone, func() {
+				location, _ := time.LoadLocation(date.timeZone)
+				parsed, err := ParseDate(clock, date.input, location)
+				Expect(err).To(BeNil())
+				Expect(parsed.Format(DATE_FORMAT)).To(Equal(date.expected))
+				Expect(parsed.Location()).To(Equal(location))
+			})
+		}
+
+		for _, input := range []string{"", "12/01/2015", "someday", "next", "+x", "31 Foo"} {
+			input := input
+			It("should not read '"+input+"'", func() {
+				_, err := ParseDate(clock, input, sydney)
+				Expect(err).NotTo(BeNil())
+			})
+		}
+	})
+
+	Describe("parsing a range of dates", func() {
+		ranges := []struct {
+			input string
+			from  string
+			to    string
+		}{
+			{"this week", "2014-12-29", "2015-01-04"},
+			{"next week", "2015-01-05", "2015-01-11"},
+			{"2015-12-01..2015-12-05", "2015-12-01", "2015-12-05"},
+			{"today..next friday", "2015-01-02", "2015-01-09"},
+			{"3 Dec .. 5 Dec", "2015-12-03", "2015-12-05"},
+			{"tomorrow", "2015-01-03", "2015-01-03"},
+		}
+
+		for _, dateRange := range ranges {
+			dateRange := dateRange
+			It("should read "+dateRange.input, func() {
+				parsed, err := ParseDateRange(clock, dateRange.input, sydney)
+				Expect(err).To(BeNil())
+				Expect(parsed.From.Format(DATE_FORMAT)).To(Equal(dateRange.from))
+				Expect(parsed.To.Format(DATE_FORMAT)).To(Equal(dateRange.to))
+			})
+		}
+
+		It("should use the time zone to find the week", func() {
+			parsed, _ := ParseDateRange(clock, "this week", newYork)
+			Expect(parsed.String()).To(Equal("29 Dec 2014 to 04 Jan 2015"))
+		})
+
+		It("should not read a range that ends before it starts", func() {
+			_, err := ParseDateRange(clock, "2015-12-05..2015-12-01", sydney)
+			Expect(err.Error()).To(Equal("The range has to start before it ends"))
+		})
+
+		It("should contain the dates at both ends", func() {
+			parsed, _ := ParseDateRange(clock, "2015-12-01..2015-12-05", sydney)
+			Expect(parsed.Contains("2015-12-01")).To(BeTrue())
+			Expect(parsed.Contains("2015-12-05")).To(BeTrue())
+			Expect(parsed.Contains("2015-12-06")).To(BeFalse())
+		})
+
+		It("should run from today with no end", func() {
+			fromToday := FromToday(clock, sydney)
+			Expect(fromToday.Contains("2015-01-01")).To(BeFalse())
+			Expect(fromToday.Contains("2015-01-02")).To(BeTrue())
+			Expect(fromToday.Contains("2099-12-31")).To(BeTrue())
+			Expect(fromToday.String()).To(Equal("from 02 Jan 2015"))
+		})
+	})
+})
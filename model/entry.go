@@ -1,9 +1,10 @@
 package model
 import (
-	"os"
 	"fmt"
 	"time"
 	"strings"
+	"encoding/json"
+	"strconv"
 )
 
 const (
@@ -27,15 +28,30 @@ type Entry struct {
 	Author    string        `json:"author"`
 	Id        string        `json:"-"`
 	StandupId int           `json:"-"`
+	Backend   string        `json:"-"`
 	ItemKind  string        `json:"-"`
+	Public    bool          `json:"public"`
 }
 
 func NewEntry(clock Clock, author, title string, standup Standup, itemKind string) *Entry {
-	location, err := time.LoadLocation(standup.TimeZone)
-	if err != nil {
-		location = time.Local
+	return &Entry{Date: clock.Now().In(standup.Location()).Format(DATE_FORMAT), Author: author, Title: title, StandupId: standup.Id, Backend: standup.Backend, ItemKind: itemKind}
+}
+
+func NewEntryType(entry *Entry) (entryType EntryType, ok bool) {
+	ok = true
+	switch entry.ItemKind {
+	case "New face":
+		entryType = Face{entry}
+	case "Interesting":
+		entryType = Interesting{entry}
+	case "Help":
+		entryType = Help{entry}
+	case "Event":
+		entryType = Event{entry}
+	default:
+		ok = false
 	}
-	return &Entry{Date: clock.Now().In(location).Format(DATE_FORMAT), Author: author, Title: title, StandupId: standup.Id, ItemKind: itemKind}
+	return
 }
 
 func (entry Entry) Validate() bool {
@@ -43,11 +59,25 @@ func (entry Entry) Validate() bool {
 }
 
 func (entry Entry) MakeCreateRequest() WhiteboardRequest {
-	return WhiteboardRequest{Token: os.Getenv("WB_AUTH_TOKEN"), Item: entry.toItem(), Commit: "Create Item"}
+	return WhiteboardRequest{Item: entry.toItem(), Commit: "Create Item"}
 }
 
 func (entry Entry) MakeUpdateRequest() WhiteboardRequest {
-	return WhiteboardRequest{Method: "patch", Token: os.Getenv("WB_AUTH_TOKEN"), Item: entry.toItem(), Commit: "Update Item", Id: entry.Id}
+	return WhiteboardRequest{Method: "patch", Item: entry.toItem(), Commit: "Update Item", Id: entry.Id}
+}
+
+func (entry *Entry) UnmarshalJSON(data []byte) error {
+	type entryFields Entry
+	var item struct {
+		entryFields
+		Id json.Number `json:"id"`
+	}
+	if err := json.Unmarshal(data, &item); err != nil {
+		return err
+	}
+	*entry = Entry(item.entryFields)
+	entry.Id = item.Id.String()
+	return nil
 }
 
 func (entry Entry) GetEntry() *Entry {
@@ -63,7 +93,18 @@ func (entry Entry) String() string {
 	if len(entry.Body) != 0 {
 		body_str = fmt.Sprintf("\n%v", entry.Body)
 	}
-	return fmt.Sprintf("*%v*%v%v\n%v", entry.Title, body_str, author_str, entry.GetDateString())
+	public_str := ""
+	if entry.Public {
+		public_str = "\n_Public_"
+	}
+	return fmt.Sprintf("*%v*%v%v\n%v%v", entry.Title, body_str, author_str, entry.GetDateString(), public_str)
+}
+
+func (entry Entry) GetVisibility() string {
+	if entry.Public {
+		return "Public"
+	}
+	return "Private"
 }
 
 func (entry Entry) GetDateString() string {
@@ -75,9 +116,36 @@ func (entry Entry) GetDateString() string {
 }
 
 func (entry Entry) toItem() Item {
-	return Item{StandupId: entry.StandupId, Title: slackUnescape(entry.Title), Date: entry.Date, Public: "false", Description: slackUnescape(entry.Body), Author: entry.Author, Kind: entry.ItemKind}
+	return Item{StandupId: entry.StandupId, Title: slackUnescape(entry.Title), Date: entry.Date, Public: strconv.FormatBool(entry.Public), Description: slackUnescape(entry.Body), Author: entry.Author, Kind: entry.ItemKind}
 }
 
 func slackUnescape(escaped string) string {
 	return strings.NewReplacer("&amp;", "&", "&lt;", "<", "&gt;", ">").Replace(escaped)
+}
+
+type storedEntry struct {
+	Kind      string `json:"kind"`
+	Id        string `json:"id"`
+	StandupId int    `json:"standup_id"`
+	Backend   string `json:"backend,omitempty"`
+	Date      string `json:"date"`
+	Title     string `json:"title"`
+	Body      string `json:"body"`
+	Author    string `json:"author"`
+	Public    bool   `json:"public"`
+}
+
+func MarshalEntry(entryType EntryType) string {
+	entry := entryType.GetEntry()
+	entryJson, _ := json.Marshal(storedEntry{Kind: entry.ItemKind, Id: entry.Id, StandupId: entry.StandupId, Backend: entry.Backend, Date: entry.Date, Title: entry.Title, Body: entry.Body, Author: entry.Author, Public: entry.Public})
+	return string(entryJson)
+}
+
+func UnmarshalEntry(entryJson string) (entryType EntryType, ok bool) {
+	var stored storedEntry
+	if err := json.Unmarshal([]byte(entryJson), &stored); err != nil {
+		return
+	}
+	entry := &Entry{Date: stored.Date, Title: stored.Title, Body: stored.Body, Author: stored.Author, Id: stored.Id, StandupId: stored.StandupId, Backend: stored.Backend, ItemKind: stored.Kind, Public: stored.Public}
+	return NewEntryType(entry)
 }
\ No newline at end of file
@@ -5,7 +5,6 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/pivotal-sydney/whiteboardbot/model"
 	"github.com/pivotal-sydney/whiteboardbot/spec"
-	"os"
 )
 
 var _ = Describe("Entry", func() {
@@ -18,7 +17,6 @@ var _ = Describe("Entry", func() {
 	BeforeEach(func() {
 		clock = spec.MockClock{}
 		entry = NewEntry(clock, "aleung", "title", Standup{Id: 1, TimeZone: "Australia/Sydney"}, "Event")
-		os.Setenv("WB_AUTH_TOKEN", "token")
 	})
 
 	Context("creating a new Entry", func() {
@@ -50,4 +48,22 @@ var _ = Describe("Entry", func() {
 			Expect(entry.Validate()).To(BeTrue())
 		})
 	})
+
+	Describe("marshalling an entry for storage", func() {
+		It("should restore the entry type with all fields", func() {
+			entry.Id = "123"
+			entry.Body = "body"
+			entry.Backend = "singapore"
+			entryType, ok := UnmarshalEntry(MarshalEntry(Event{entry}))
+			Expect(ok).To(BeTrue())
+			Expect(entryType).To(BeAssignableToTypeOf(Event{}))
+			Expect(entryType.GetEntry()).To(Equal(entry))
+		})
+
+		It("should not restore an entry with unknown kind", func() {
+			entry.ItemKind = "Unknown"
+			_, ok := UnmarshalEntry(MarshalEntry(Event{entry}))
+			Expect(ok).To(BeFalse())
+		})
+	})
 })
\ No newline at end of file
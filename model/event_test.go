@@ -5,7 +5,6 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/pivotal-sydney/whiteboardbot/model"
 	"github.com/pivotal-sydney/whiteboardbot/spec"
-	"os"
 )
 
 var _ = Describe("Event", func() {
@@ -18,7 +17,6 @@ var _ = Describe("Event", func() {
 	BeforeEach(func() {
 		clock = spec.MockClock{}
 		event = NewEvent(clock, "aleung", "title", Standup{Id: 1, TimeZone: "Australia/Sydney"}).(Event)
-		os.Setenv("WB_AUTH_TOKEN", "token")
 	})
 
 	Describe("creating a new Event", func() {
@@ -53,7 +51,7 @@ var _ = Describe("Event", func() {
 				request := event.MakeCreateRequest()
 				Expect(request.Utf8).To(Equal(""))
 				Expect(request.Method).To(Equal(""))
-				Expect(request.Token).To(Equal("token"))
+				Expect(request.Token).To(BeEmpty())
 				Expect(request.Commit).To(Equal("Create Item"))
 				Expect(request.Id).To(Equal(""))
 				Expect(request.Item.StandupId).To(Equal(1))
@@ -72,7 +70,7 @@ var _ = Describe("Event", func() {
 				request := event.MakeUpdateRequest()
 				Expect(request.Utf8).To(Equal(""))
 				Expect(request.Method).To(Equal("patch"))
-				Expect(request.Token).To(Equal("token"))
+				Expect(request.Token).To(BeEmpty())
 				Expect(request.Commit).To(Equal("Update Item"))
 				Expect(request.Id).To(Equal(event.Id))
 				Expect(request.Item.StandupId).To(Equal(1))
@@ -5,7 +5,6 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/pivotal-sydney/whiteboardbot/model"
 	"github.com/pivotal-sydney/whiteboardbot/spec"
-	"os"
 )
 
 var _ = Describe("Face", func() {
@@ -18,7 +17,6 @@ var _ = Describe("Face", func() {
 	BeforeEach(func() {
 		clock = spec.MockClock{}
 		face = NewFace(clock, "aleung", "title", Standup{Id: 1, TimeZone: "Australia/Sydney"}).(Face)
-		os.Setenv("WB_AUTH_TOKEN", "token")
 	})
 
 	Describe("creating a new Face", func() {
@@ -51,7 +49,7 @@ var _ = Describe("Face", func() {
 				request := face.MakeCreateRequest()
 				Expect(request.Utf8).To(Equal(""))
 				Expect(request.Method).To(Equal(""))
-				Expect(request.Token).To(Equal("token"))
+				Expect(request.Token).To(BeEmpty())
 				Expect(request.Commit).To(Equal("Create New Face"))
 				Expect(request.Id).To(Equal(""))
 				Expect(request.Item.StandupId).To(Equal(1))
@@ -70,7 +68,7 @@ var _ = Describe("Face", func() {
 				request := face.MakeUpdateRequest()
 				Expect(request.Utf8).To(Equal(""))
 				Expect(request.Method).To(Equal("patch"))
-				Expect(request.Token).To(Equal("token"))
+				Expect(request.Token).To(BeEmpty())
 				Expect(request.Commit).To(Equal("Update New Face"))
 				Expect(request.Id).To(Equal(face.Id))
 				Expect(request.Item.StandupId).To(Equal(1))
@@ -5,7 +5,6 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/pivotal-sydney/whiteboardbot/model"
 	"github.com/pivotal-sydney/whiteboardbot/spec"
-	"os"
 )
 
 var _ = Describe("Help", func() {
@@ -18,7 +17,6 @@ var _ = Describe("Help", func() {
 	BeforeEach(func() {
 		clock = spec.MockClock{}
 		help = NewHelp(clock, "aleung", "title", Standup{Id: 1, TimeZone: "Australia/Sydney"}).(Help)
-		os.Setenv("WB_AUTH_TOKEN", "token")
 	})
 
 	Describe("creating a new Help", func() {
@@ -61,7 +59,7 @@ var _ = Describe("Help", func() {
 				request := help.MakeCreateRequest()
 				Expect(request.Utf8).To(Equal(""))
 				Expect(request.Method).To(Equal(""))
-				Expect(request.Token).To(Equal("token"))
+				Expect(request.Token).To(BeEmpty())
 				Expect(request.Commit).To(Equal("Create Item"))
 				Expect(request.Id).To(Equal(""))
 				Expect(request.Item.StandupId).To(Equal(1))
@@ -80,7 +78,7 @@ var _ = Describe("Help", func() {
 				request := help.MakeUpdateRequest()
 				Expect(request.Utf8).To(Equal(""))
 				Expect(request.Method).To(Equal("patch"))
-				Expect(request.Token).To(Equal("token"))
+				Expect(request.Token).To(BeEmpty())
 				Expect(request.Commit).To(Equal("Update Item"))
 				Expect(request.Id).To(Equal(help.Id))
 				Expect(request.Item.StandupId).To(Equal(1))
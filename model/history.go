@@ -0,0 +1,95 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EntryFields are the details of an entry that can be edited, and so undone.
+type EntryFields struct {
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	Date   string `json:"date"`
+	Public bool   `json:"public"`
+}
+
+type EntryChange struct {
+	Author string      `json:"author"`
+	Time   time.Time   `json:"time"`
+	Before EntryFields `json:"before"`
+	After  EntryFields `json:"after"`
+}
+
+// EntryHistory lists the edits made to an entry. Position counts the changes that are applied, the ones after it have
+// been undone and can be redone until the entry is edited again.
+type EntryHistory struct {
+	Changes  []EntryChange `json:"changes"`
+	Position int           `json:"position"`
+}
+
+func FieldsOf(entry *Entry) EntryFields {
+	return EntryFields{Title: entry.Title, Body: entry.Body, Date: entry.Date, Public: entry.Public}
+}
+
+func (fields EntryFields) ApplyTo(entry *Entry) {
+	entry.Title = fields.Title
+	entry.Body = fields.Body
+	entry.Date = fields.Date
+	entry.Public = fields.Public
+}
+
+func (history *EntryHistory) Record(change EntryChange) {
+	history.Changes = append(history.Changes[:history.Position], change)
+	history.Position = len(history.Changes)
+}
+
+// Undo returns the fields from before the last change, and Redo the fields from after the last undone change. Neither
+// moves Position, as the fields still have to make it to the whiteboard; use Undone and Redone once they have.
+func (history EntryHistory) Undo() (fields EntryFields, ok bool) {
+	if history.Position == 0 {
+		return
+	}
+	return history.Changes[history.Position - 1].Before, true
+}
+
+func (history EntryHistory) Redo() (fields EntryFields, ok bool) {
+	if history.Position >= len(history.Changes) {
+		return
+	}
+	return history.Changes[history.Position].After, true
+}
+
+func (history *EntryHistory) Undone() {
+	history.Position--
+}
+
+func (history *EntryHistory) Redone() {
+	history.Position++
+}
+
+// Describe lists what the change did to each field, like `title: Old → New`.
+func (change EntryChange) Describe() string {
+	var differences []string
+	describe := func(field string, before string, after string) {
+		if before != after {
+			differences = append(differences, fmt.Sprintf("%v: %v → %v", field, orNothing(before), orNothing(after)))
+		}
+	}
+	describe("title", change.Before.Title, change.After.Title)
+	describe("body", change.Before.Body, change.After.Body)
+	describe("date", change.Before.Date, change.After.Date)
+	describe("visibility", visibility(change.Before.Public), visibility(change.After.Public))
+	return strings.Join(differences, ", ")
+}
+
+func visibility(public bool) string {
+	return Entry{Public: public}.GetVisibility()
+}
+
+func orNothing(value string) string {
+	if len(value) == 0 {
+		return "_nothing_"
+	}
+	return value
+}
@@ -0,0 +1,52 @@
+package model_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+)
+
+var _ = Describe("EntryHistory", func() {
+	var (
+		history       EntryHistory
+		first, second EntryChange
+	)
+
+	BeforeEach(func() {
+		history = EntryHistory{}
+		first = EntryChange{Before: EntryFields{Title: "Old"}, After: EntryFields{Title: "New"}}
+		second = EntryChange{Before: EntryFields{Title: "New"}, After: EntryFields{Title: "New", Body: "Body", Public: true}}
+		history.Record(first)
+		history.Record(second)
+	})
+
+	It("should undo and redo the changes in order", func() {
+		fields, ok := history.Undo()
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(Equal(second.Before))
+		history.Undone()
+
+		fields, _ = history.Undo()
+		Expect(fields).To(Equal(first.Before))
+		history.Undone()
+		_, ok = history.Undo()
+		Expect(ok).To(BeFalse())
+
+		fields, ok = history.Redo()
+		Expect(ok).To(BeTrue())
+		Expect(fields).To(Equal(first.After))
+	})
+
+	It("should drop undone changes when a new one is recorded", func() {
+		history.Undone()
+		history.Record(EntryChange{Before: EntryFields{Title: "New"}, After: EntryFields{Title: "Newer"}})
+		Expect(history.Changes).To(HaveLen(2))
+		_, ok := history.Redo()
+		Expect(ok).To(BeFalse())
+	})
+
+	It("should describe what changed", func() {
+		Expect(first.Describe()).To(Equal("title: Old → New"))
+		Expect(second.Describe()).To(Equal("body: _nothing_ → Body, visibility: Private → Public"))
+	})
+})
@@ -5,7 +5,6 @@ import (
 	. "github.com/onsi/gomega"
 	. "github.com/pivotal-sydney/whiteboardbot/model"
 	"github.com/pivotal-sydney/whiteboardbot/spec"
-	"os"
 )
 
 var _ = Describe("Interesting", func() {
@@ -18,7 +17,6 @@ var _ = Describe("Interesting", func() {
 	BeforeEach(func() {
 		clock = spec.MockClock{}
 		interesting = NewInteresting(clock, "aleung", "title", Standup{Id: 1, TimeZone: "Australia/Sydney"}).(Interesting)
-		os.Setenv("WB_AUTH_TOKEN", "token")
 	})
 
 	Describe("creating a new Interesting", func() {
@@ -39,6 +37,13 @@ var _ = Describe("Interesting", func() {
 			interesting.Author = "some author"
 			Expect(interesting.String()).To(Equal("*some title*\nsome body\n[some author]\n02 Jan 2015"))
 		})
+
+		It("should show when the interesting is public", func() {
+			interesting.Title = "some title"
+			interesting.Author = "some author"
+			interesting.Public = true
+			Expect(interesting.String()).To(Equal("*some title*\n[some author]\n02 Jan 2015\n_Public_"))
+		})
 	})
 
 	Context("when making requets", func() {
@@ -53,7 +58,7 @@ var _ = Describe("Interesting", func() {
 				request := interesting.MakeCreateRequest()
 				Expect(request.Utf8).To(Equal(""))
 				Expect(request.Method).To(Equal(""))
-				Expect(request.Token).To(Equal("token"))
+				Expect(request.Token).To(BeEmpty())
 				Expect(request.Commit).To(Equal("Create Item"))
 				Expect(request.Id).To(Equal(""))
 				Expect(request.Item.StandupId).To(Equal(1))
@@ -72,7 +77,7 @@ var _ = Describe("Interesting", func() {
 				request := interesting.MakeUpdateRequest()
 				Expect(request.Utf8).To(Equal(""))
 				Expect(request.Method).To(Equal("patch"))
-				Expect(request.Token).To(Equal("token"))
+				Expect(request.Token).To(BeEmpty())
 				Expect(request.Commit).To(Equal("Update Item"))
 				Expect(request.Id).To(Equal(interesting.Id))
 				Expect(request.Item.StandupId).To(Equal(1))
@@ -0,0 +1,56 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+type Post struct {
+	Id    string       `json:"-"`
+	Title string       `json:"title"`
+	Items StandupItems `json:"items"`
+}
+
+type PostRequest struct {
+	Utf8   string      `json:"utf8"`
+	Method string      `json:"_method,omitempty"`
+	Token  string      `json:"authenticity_token"`
+	Post   PostDetails `json:"post"`
+	Commit string      `json:"commit,omitempty"`
+	Id     string      `json:"id,omitempty"`
+}
+
+type PostDetails struct {
+	StandupId int    `json:"standup_id,omitempty"`
+	Title     string `json:"title,omitempty"`
+}
+
+func NewPostRequest(standupId int, title string) PostRequest {
+	return PostRequest{Post: PostDetails{StandupId: standupId, Title: title}, Commit: "Create Post"}
+}
+
+func NewSendEmailRequest(postId string) PostRequest {
+	return PostRequest{Method: "put", Id: postId}
+}
+
+func (request *PostRequest) SetToken(token string) {
+	request.Token = token
+}
+
+func (post *Post) UnmarshalJSON(data []byte) error {
+	type postFields Post
+	var fields struct {
+		postFields
+		Id json.Number `json:"id"`
+	}
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*post = Post(fields.postFields)
+	post.Id = fields.Id.String()
+	return nil
+}
+
+func (post Post) String() string {
+	return fmt.Sprintf("*%v*\n%v", post.Title, post.Items)
+}
@@ -0,0 +1,126 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DEFAULT_REMINDER_MINUTES = 10
+)
+
+var (
+	weekdays = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday}
+	everyDay = []time.Weekday{time.Sunday, time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday}
+)
+
+// Schedule is when a channel's standup gets presented, in the standup's time zone.
+type Schedule struct {
+	Days            []time.Weekday `json:"days"`
+	Hour            int            `json:"hour"`
+	Minute          int            `json:"minute"`
+	ReminderMinutes int            `json:"reminder_minutes"`
+}
+
+// ParseSchedule reads schedules like `weekdays 09:05`, `mon,wed,fri 9:30 remind 5` or `daily 10:00 remind 0`.
+func ParseSchedule(input string) (schedule Schedule, err error) {
+	schedule = Schedule{Days: weekdays, Hour: -1, ReminderMinutes: DEFAULT_REMINDER_MINUTES}
+	fields := strings.Fields(strings.ToLower(input))
+	for i := 0; i < len(fields); i++ {
+		field := fields[i]
+		switch {
+		case field == "weekdays":
+			schedule.Days = weekdays
+		case field == "daily" || field == "everyday":
+			schedule.Days = everyDay
+		case field == "remind" && i + 1 < len(fields):
+			i++
+			if schedule.ReminderMinutes, err = strconv.Atoi(fields[i]); err != nil || schedule.ReminderMinutes < 0 {
+				return schedule, fmt.Errorf("%v isn't a number of minutes", fields[i])
+			}
+		case strings.Contains(field, ":"):
+			if schedule.Hour, schedule.Minute, err = parseTimeOfDay(field); err != nil {
+				return
+			}
+		default:
+			if schedule.Days, err = parseDays(field); err != nil {
+				return
+			}
+		}
+	}
+	if schedule.Hour < 0 {
+		err = errors.New("I need a time for the standup, like 09:05")
+	}
+	return
+}
+
+func parseTimeOfDay(field string) (hour int, minute int, err error) {
+	parsed, err := time.Parse("15:04", field)
+	if err != nil {
+		return 0, 0, fmt.Errorf("%v isn't a time, use 24 hour time like 09:05", field)
+	}
+	return parsed.Hour(), parsed.Minute(), nil
+}
+
+func parseDays(field string) (days []time.Weekday, err error) {
+	for _, name := range strings.Split(field, ",") {
+		day, ok := parseWeekday(name)
+		if !ok {
+			return nil, fmt.Errorf("%v isn't a day I know", name)
+		}
+		days = append(days, day)
+	}
+	return
+}
+
+// Due reports whether the standup or its reminder fell due after from and up to and including to.
+func (schedule Schedule) Due(from time.Time, to time.Time, location *time.Location) (present bool, remind bool) {
+	reminder := time.Duration(schedule.ReminderMinutes) * time.Minute
+	last := to.In(location).AddDate(0, 0, 1)
+	for day := from.In(location).AddDate(0, 0, -1); !day.After(last); day = day.AddDate(0, 0, 1) {
+		if !schedule.runsOn(day.Weekday()) {
+			continue
+		}
+		presentAt := time.Date(day.Year(), day.Month(), day.Day(), schedule.Hour, schedule.Minute, 0, 0, location)
+		present = present || within(presentAt, from, to)
+		remind = remind || reminder > 0 && within(presentAt.Add(-reminder), from, to)
+	}
+	return
+}
+
+func (schedule Schedule) runsOn(weekday time.Weekday) bool {
+	for _, day := range schedule.Days {
+		if day == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+func within(moment time.Time, from time.Time, to time.Time) bool {
+	return moment.After(from) && !moment.After(to)
+}
+
+func (schedule Schedule) String() string {
+	var days string
+	switch {
+	case len(schedule.Days) == len(everyDay):
+		days = "every day"
+	case fmt.Sprint(schedule.Days) == fmt.Sprint(weekdays):
+		days = "weekdays"
+	default:
+		names := make([]string, len(schedule.Days))
+		for i, day := range schedule.Days {
+			names[i] = day.String()[:3]
+		}
+		days = "on " + strings.Join(names, ", ")
+	}
+	reminder := ""
+	if schedule.ReminderMinutes > 0 {
+		reminder = fmt.Sprintf(", with a reminder %v minutes before", schedule.ReminderMinutes)
+	}
+	return fmt.Sprintf("%v at %02d:%02d%v", days, schedule.Hour, schedule.Minute, reminder)
+}
@@ -0,0 +1,69 @@
+package model_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/model"
+	"time"
+)
+
+var _ = Describe("Schedule", func() {
+
+	Describe("parsing", func() {
+		It("should default to weekdays with a reminder", func() {
+			schedule, err := ParseSchedule("9:05")
+			Expect(err).To(BeNil())
+			Expect(schedule.String()).To(Equal("weekdays at 09:05, with a reminder 10 minutes before"))
+		})
+
+		It("should read days and a reminder", func() {
+			schedule, err := ParseSchedule("Mon,Wednesday,fri 13:30 remind 5")
+			Expect(err).To(BeNil())
+			Expect(schedule.Days).To(Equal([]time.Weekday{time.Monday, time.Wednesday, time.Friday}))
+			Expect(schedule.String()).To(Equal("on Mon, Wed, Fri at 13:30, with a reminder 5 minutes before"))
+		})
+
+		It("should turn the reminder off", func() {
+			schedule, _ := ParseSchedule("daily 10:00 remind 0")
+			Expect(schedule.String()).To(Equal("every day at 10:00"))
+		})
+
+		It("should need a time", func() {
+			_, err := ParseSchedule("weekdays")
+			Expect(err.Error()).To(Equal("I need a time for the standup, like 09:05"))
+		})
+
+		It("should reject what it doesn't know", func() {
+			_, err := ParseSchedule("weekdays 25:00")
+			Expect(err.Error()).To(Equal("25:00 isn't a time, use 24 hour time like 09:05"))
+			_, err = ParseSchedule("someday 09:00")
+			Expect(err.Error()).To(Equal("someday isn't a day I know"))
+		})
+	})
+
+	Describe("finding what is due", func() {
+		var (
+			schedule Schedule
+			sydney   *time.Location
+		)
+
+		BeforeEach(func() {
+			schedule, _ = ParseSchedule("weekdays 00:05")
+			sydney, _ = time.LoadLocation("Australia/Sydney")
+		})
+
+		It("should remind on the day before when standup is just after midnight", func() {
+			present, remind := schedule.Due(time.Date(2015, 1, 4, 23, 50, 0, 0, sydney), time.Date(2015, 1, 4, 23, 55, 0, 0, sydney), sydney)
+			Expect(present).To(BeFalse())
+			Expect(remind).To(BeTrue())
+		})
+
+		It("should not present twice for the same moment", func() {
+			moment := time.Date(2015, 1, 5, 0, 5, 0, 0, sydney)
+			present, _ := schedule.Due(moment.Add(-time.Minute), moment, sydney)
+			Expect(present).To(BeTrue())
+			present, _ = schedule.Due(moment, moment.Add(time.Minute), sydney)
+			Expect(present).To(BeFalse())
+		})
+	})
+})
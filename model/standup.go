@@ -1,7 +1,20 @@
 package model
 
+import "time"
+
 type Standup struct {
 	Id int				`json:"id"`
 	TimeZone string		`json:"time_zone_name_iana"`
 	Title string		`json:"title"`
-}
\ No newline at end of file
+	Backend string		`json:"backend,omitempty"`
+	Alias string		`json:"alias,omitempty"`
+}
+
+// Location is the standup's time zone, or the local one when the Whiteboard doesn't know it.
+func (standup Standup) Location() *time.Location {
+	location, err := time.LoadLocation(standup.TimeZone)
+	if err != nil {
+		return time.Local
+	}
+	return location
+}
@@ -5,6 +5,15 @@ import (
 	"bytes"
 )
 
+const (
+	FACES_SECTION = "faces"
+	HELPS_SECTION = "helps"
+	INTERESTINGS_SECTION = "interestings"
+	EVENTS_SECTION = "events"
+)
+
+var DEFAULT_SECTION_ORDER = []string{FACES_SECTION, HELPS_SECTION, INTERESTINGS_SECTION, EVENTS_SECTION}
+
 type StandupItems struct {
 	Helps        []Entry            `json:"Help"`
 	Interestings []Entry  			`json:"Interesting"`
@@ -38,9 +47,110 @@ func toString(typeName string, entries []Entry) string {
 }
 
 func (items StandupItems) String() string {
-	return fmt.Sprintf(">>>— — —\n \n \n \n%v\n \n \n \n%v\n \n \n \n%v\n \n \n \n%v\n \n \n \n— — —\n:clap:", items.FacesString(), items.HelpsString(), items.InterestingsString(), items.EventsString())
+	return items.Present(DEFAULT_SECTION_ORDER)
+}
+
+// Present shows the given sections in order, leaving out the ones without any entries.
+func (items StandupItems) Present(sections []string) string {
+	var sectionStrings []string
+	for _, section := range sections {
+		if len(items.sectionEntries(section)) > 0 {
+			sectionStrings = append(sectionStrings, items.SectionString(section))
+		}
+	}
+	return fmt.Sprintf(">>>— — —\n \n \n \n%v\n \n \n \n— — —\n:clap:", strings.Join(sectionStrings, "\n \n \n \n"))
+}
+
+func (items StandupItems) SectionString(section string) string {
+	switch section {
+	case FACES_SECTION:
+		return items.FacesString()
+	case HELPS_SECTION:
+		return items.HelpsString()
+	case INTERESTINGS_SECTION:
+		return items.InterestingsString()
+	case EVENTS_SECTION:
+		return items.EventsString()
+	}
+	return ""
+}
+
+// FilterDates keeps the entries dated within the range, along with any entries whose date can't be read.
+func (items StandupItems) FilterDates(dateRange DateRange) StandupItems {
+	return StandupItems{Helps: dateRange.Filter(items.Helps), Interestings: dateRange.Filter(items.Interestings), Faces: dateRange.Filter(items.Faces), Events: dateRange.Filter(items.Events)}
+}
+
+// HasEntries reports whether any of the given sections has an entry.
+func (items StandupItems) HasEntries(sections []string) bool {
+	for _, section := range sections {
+		if len(items.sectionEntries(section)) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func (items StandupItems) sectionEntries(section string) []Entry {
+	switch section {
+	case FACES_SECTION:
+		return items.Faces
+	case HELPS_SECTION:
+		return items.Helps
+	case INTERESTINGS_SECTION:
+		return items.Interestings
+	case EVENTS_SECTION:
+		return items.Events
+	}
+	return nil
+}
+
+// ParseSection finds the section a name or abbreviation like `h` or `help` refers to.
+func ParseSection(name string) (section string, ok bool) {
+	name = strings.ToLower(name)
+	for _, section = range DEFAULT_SECTION_ORDER {
+		if len(name) > 0 && strings.HasPrefix(section, name) {
+			return section, true
+		}
+	}
+	return "", false
 }
 
 func (items StandupItems) Empty() bool {
 	return len(items.Faces) == 0 && len(items.Events) == 0 && len(items.Helps) == 0 && len(items.Interestings) == 0
+}
+
+func (items StandupItems) Find(itemId string) (entryType EntryType, ok bool) {
+	for _, entryType = range items.EntryTypes() {
+		if entryType.GetEntry().Id == itemId {
+			return entryType, true
+		}
+	}
+	return nil, false
+}
+
+func (items StandupItems) Search(text string) (found []EntryType) {
+	text = strings.ToLower(text)
+	for _, entryType := range items.EntryTypes() {
+		entry := entryType.GetEntry()
+		if strings.Contains(strings.ToLower(entry.Title), text) || strings.Contains(strings.ToLower(entry.Body), text) {
+			found = append(found, entryType)
+		}
+	}
+	return
+}
+
+func (items StandupItems) EntryTypes() (entryTypes []EntryType) {
+	sections := []struct {
+		kind    string
+		entries []Entry
+	}{{"New face", items.Faces}, {"Help", items.Helps}, {"Interesting", items.Interestings}, {"Event", items.Events}}
+	for _, section := range sections {
+		for i := range section.entries {
+			entry := section.entries[i]
+			entry.ItemKind = section.kind
+			entryType, _ := NewEntryType(&entry)
+			entryTypes = append(entryTypes, entryType)
+		}
+	}
+	return
 }
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 	. "github.com/pivotal-sydney/whiteboardbot/model"
 	"github.com/pivotal-sydney/whiteboardbot/model"
 	"fmt"
+	"encoding/json"
 )
 
 var _ = Describe("StandupItems", func() {
@@ -25,6 +26,28 @@ var _ = Describe("StandupItems", func() {
 			itemsString := items.String()
 			Expect(itemsString).To(Equal(fmt.Sprintf(">>>— — —\n \n \n \n%v\n \n \n \n%v\n \n \n \n%v\n \n \n \n%v\n \n \n \n— — —\n:clap:", items.FacesString(), items.HelpsString(), items.InterestingsString(), items.EventsString())))
 		})
+
+		It("should leave out empty sections", func() {
+			items.Faces = nil
+			itemsString := items.String()
+			Expect(itemsString).To(Equal(fmt.Sprintf(">>>— — —\n \n \n \n%v\n \n \n \n%v\n \n \n \n%v\n \n \n \n— — —\n:clap:", items.HelpsString(), items.InterestingsString(), items.EventsString())))
+		})
+
+		It("should present sections in the order given", func() {
+			itemsString := items.Present([]string{EVENTS_SECTION, HELPS_SECTION})
+			Expect(itemsString).To(Equal(fmt.Sprintf(">>>— — —\n \n \n \n%v\n \n \n \n%v\n \n \n \n— — —\n:clap:", items.EventsString(), items.HelpsString())))
+		})
+	})
+
+	Describe("parsing a section name", func() {
+		It("should accept abbreviations", func() {
+			section, _ := ParseSection("h")
+			Expect(section).To(Equal(HELPS_SECTION))
+			section, _ = ParseSection("Interesting")
+			Expect(section).To(Equal(INTERESTINGS_SECTION))
+			_, ok := ParseSection("lunch")
+			Expect(ok).To(BeFalse())
+		})
 	})
 
 	Describe("convert standup faces items to string", func() {
@@ -54,4 +77,33 @@ var _ = Describe("StandupItems", func() {
 			Expect(itemsString).To(Equal("EVENTS\n\n" + Event{&items.Events[0]}.String() + "\n \n" + Event{&items.Events[1]}.String()))
 		})
 	})
+
+	Describe("finding an item by id", func() {
+		It("should return the item with its kind", func() {
+			var standupItems StandupItems
+			err := json.Unmarshal([]byte(`{"Help": [{"id": 42, "title": "Help me!", "author": "Lawrence", "date": "2015-12-03"}]}`), &standupItems)
+			Expect(err).To(BeNil())
+
+			entryType, ok := standupItems.Find("42")
+			Expect(ok).To(BeTrue())
+			Expect(entryType).To(BeAssignableToTypeOf(Help{}))
+			Expect(entryType.GetEntry().Title).To(Equal("Help me!"))
+			Expect(entryType.GetEntry().ItemKind).To(Equal("Help"))
+		})
+
+		It("should not find a missing item", func() {
+			_, ok := items.Find("42")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("searching items", func() {
+		It("should return items with matching title or body in presentation order", func() {
+			found := items.Search("ANOTHER")
+			Expect(found).To(HaveLen(2))
+			Expect(found[0].GetEntry().Title).To(Equal("Another meetup"))
+			Expect(found[1].GetEntry().Title).To(Equal("Another bloody meetup"))
+			Expect(found[0]).To(BeAssignableToTypeOf(Event{}))
+		})
+	})
 })
\ No newline at end of file
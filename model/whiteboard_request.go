@@ -1,4 +1,5 @@
 package model
+
 type WhiteboardRequest struct {
 	Utf8   string `json:"utf8"`
 	Method string `json:"_method,omitempty"`
@@ -17,4 +18,12 @@ type Item struct {
 	Kind        string `json:"kind"`
 	Description string `json:"description,omitempty"`
 	Author      string `json:"author,omitempty"`
-}
\ No newline at end of file
+}
+
+func NewDeleteRequest(itemId string) WhiteboardRequest {
+	return WhiteboardRequest{Method: "delete", Id: itemId}
+}
+
+func (request *WhiteboardRequest) SetToken(token string) {
+	request.Token = token
+}
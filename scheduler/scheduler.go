@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"github.com/pivotal-sydney/whiteboardbot/app"
+	"sync"
+	"time"
+)
+
+const (
+	TICK_INTERVAL = time.Minute
+)
+
+// Scheduler presents each channel's standup, and reminds the channel beforehand, at the times set with `wb schedule`.
+type Scheduler struct {
+	Whiteboard app.WhiteboardApp
+	lastTick   time.Time
+	mutex      sync.Mutex
+}
+
+func NewScheduler(whiteboard app.WhiteboardApp) *Scheduler {
+	return &Scheduler{Whiteboard: whiteboard, lastTick: whiteboard.Clock.Now()}
+}
+
+func (scheduler *Scheduler) Run(stop <-chan struct{}) {
+	ticker := time.NewTicker(TICK_INTERVAL)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			scheduler.Tick()
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Tick presents and reminds every standup that fell due since the last tick.
+func (scheduler *Scheduler) Tick() {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+
+	now := scheduler.Whiteboard.Clock.Now()
+	from := scheduler.lastTick
+	scheduler.lastTick = now
+	if !now.After(from) {
+		return
+	}
+
+	for _, channel := range scheduler.Whiteboard.ScheduledChannels() {
+		for _, scheduled := range scheduler.Whiteboard.ScheduledStandups(channel) {
+			present, remind := scheduled.Schedule.Due(from, now, scheduled.Standup.Location())
+			if remind {
+				scheduler.Whiteboard.RemindStandup(channel, scheduled.Standup, scheduled.Schedule.ReminderMinutes)
+			}
+			if present {
+				scheduler.Whiteboard.PresentStandup(channel, scheduled.Standup)
+			}
+		}
+	}
+}
@@ -0,0 +1,111 @@
+package scheduler_test
+
+import (
+	"github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+	. "github.com/pivotal-sydney/whiteboardbot/scheduler"
+	"github.com/pivotal-sydney/whiteboardbot/spec"
+	"time"
+)
+
+var _ = Describe("Scheduler", func() {
+
+	var (
+		clock       *spec.FakeClock
+		slackClient *spec.MockSlackClient
+		restClient  *spec.MockRestClient
+		whiteboard  WhiteboardApp
+		scheduler   *Scheduler
+		sydney      *time.Location
+	)
+
+	command := func(text string) {
+		ev := slack.MessageEvent{Msg: slack.Msg{Text: text, User: "aleung", Channel: "whiteboard-sydney"}}
+		whiteboard.ParseMessageEvent(&ev)
+	}
+
+	advanceTo := func(moment time.Time) {
+		clock.CurrentTime = moment
+		scheduler.Tick()
+	}
+
+	BeforeEach(func() {
+		sydney, _ = time.LoadLocation("Australia/Sydney")
+		clock = &spec.FakeClock{CurrentTime: time.Date(2015, 1, 2, 8, 0, 0, 0, sydney)}
+		slackClient = &spec.MockSlackClient{}
+		restClient = &spec.MockRestClient{}
+		restClient.StandupItems.Helps = []model.Entry{model.Entry{Id: "42", Title: "Help me!", Author: "Andrew Leung", Date: "2015-01-02"}}
+		whiteboard = NewWhiteboard(slackClient, restClient, clock, &spec.MockStore{})
+
+		command("wb r 1")
+		command("wb schedule weekdays 09:05")
+		slackClient.Message = ""
+		scheduler = NewScheduler(whiteboard)
+	})
+
+	It("should do nothing before the reminder", func() {
+		advanceTo(time.Date(2015, 1, 2, 8, 54, 0, 0, sydney))
+		Expect(slackClient.Message).To(BeEmpty())
+	})
+
+	It("should remind the channel before standup", func() {
+		advanceTo(time.Date(2015, 1, 2, 8, 55, 0, 0, sydney))
+		Expect(slackClient.Message).To(HavePrefix("Standup starts in 10 minutes!"))
+	})
+
+	It("should present the standup on time", func() {
+		advanceTo(time.Date(2015, 1, 2, 9, 5, 30, 0, sydney))
+		Expect(slackClient.Message).To(ContainSubstring("*Help me!*"))
+	})
+
+	It("should only present once", func() {
+		advanceTo(time.Date(2015, 1, 2, 9, 5, 0, 0, sydney))
+		slackClient.Message = ""
+		advanceTo(time.Date(2015, 1, 2, 9, 6, 0, 0, sydney))
+		Expect(slackClient.Message).To(BeEmpty())
+	})
+
+	It("should catch up on a late tick", func() {
+		clock.Advance(3 * time.Hour)
+		scheduler.Tick()
+		Expect(slackClient.Message).To(ContainSubstring("*Help me!*"))
+	})
+
+	It("should use the standup's time zone", func() {
+		advanceTo(time.Date(2015, 1, 2, 9, 5, 0, 0, time.UTC).Add(-time.Minute))
+		slackClient.Message = ""
+		advanceTo(time.Date(2015, 1, 2, 9, 5, 0, 0, time.UTC))
+		Expect(slackClient.Message).To(BeEmpty())
+	})
+
+	It("should skip the weekend", func() {
+		advanceTo(time.Date(2015, 1, 2, 10, 0, 0, 0, sydney))
+		slackClient.Message = ""
+		advanceTo(time.Date(2015, 1, 4, 10, 0, 0, 0, sydney))
+		Expect(slackClient.Message).To(BeEmpty())
+		restClient.StandupItems.Helps[0].Date = "2015-01-05"
+		advanceTo(time.Date(2015, 1, 5, 9, 5, 0, 0, sydney))
+		Expect(slackClient.Message).To(ContainSubstring("*Help me!*"))
+	})
+
+	It("should present a named standup from its own whiteboard", func() {
+		command("wb r singapore 12 as sg")
+		command("wb @sg schedule weekdays 09:30 remind 5")
+		advanceTo(time.Date(2015, 1, 2, 9, 25, 0, 0, sydney))
+		Expect(slackClient.Message).To(HavePrefix("Standup Sydney starts in 5 minutes! Add your entries with `wb @sg [face interesting help event] [title]`"))
+		restClient.Backend = ""
+		advanceTo(time.Date(2015, 1, 2, 9, 30, 0, 0, sydney))
+		Expect(slackClient.Message).To(ContainSubstring("*Help me!*"))
+		Expect(restClient.Backend).To(Equal("singapore"))
+	})
+
+	It("should stop once the schedule is turned off", func() {
+		command("wb schedule off")
+		slackClient.Message = ""
+		advanceTo(time.Date(2015, 1, 2, 9, 5, 0, 0, sydney))
+		Expect(slackClient.Message).To(BeEmpty())
+	})
+})
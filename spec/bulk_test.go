@@ -0,0 +1,161 @@
+package spec
+
+import (
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"errors"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+)
+
+var _ = Describe("Bulk Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		restClient  *MockRestClient
+
+		bulkEvent, badLinesEvent, emptyEvent, undoEvent MessageEvent
+	)
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+
+		bulkEvent = createMessageEvent("wb bulk\ni: Something interesting | with a body\n\nh: Help me --date 2015-01-05\nf: New person")
+		badLinesEvent = createMessageEvent("wb bulk\nx: Nothing\ni:\ne: Meetup --date someday")
+		emptyEvent = createMessageEvent("wb bulk")
+		undoEvent = createMessageEvent("wb undo")
+	})
+
+	It("should create an entry for each line and report on them", func() {
+		whiteboard.ParseMessageEvent(&bulkEvent)
+		Expect(restClient.PostCalledCount).To(Equal(3))
+		Expect(restClient.Request.Item.Kind).To(Equal("New face"))
+		Expect(slackClient.Message).To(Equal("Line 1: created interesting *Something interesting* 02 Jan 2015\n" +
+			"Line 2: created help *Help me* 05 Jan 2015\n" +
+			"Line 3: created new face *New person* 02 Jan 2015\n\n" +
+			"Created 3 of 3 entries. Changed your mind? `wb undo` deletes them again."))
+		Expect(slackClient.Status).To(Equal(THUMBS_UP))
+		author, _ := whiteboard.Store.Get(AuthorKey("", "1"))
+		Expect(author).To(Equal("aleung"))
+	})
+
+	It("should report the lines it couldn't create", func() {
+		whiteboard.ParseMessageEvent(&badLinesEvent)
+		Expect(restClient.PostCalledCount).To(Equal(0))
+		Expect(slackClient.Message).To(Equal("Line 1: Start the line with the kind of entry, like `i: Title`, `h: Title`, `e: Title` or `f: Name`\n" +
+			"Line 2: The title/name can't be empty!\n" +
+			"Line 3: I don't understand the date: someday\n\n" +
+			"Created 0 of 3 entries."))
+		Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+	})
+
+	It("should report entries the whiteboard didn't accept", func() {
+		restClient.PostError = errors.New("boom")
+		whiteboard.ParseMessageEvent(&bulkEvent)
+		Expect(slackClient.Message).To(HavePrefix("Line 1: Something went wrong talking to the Whiteboard: boom\n"))
+		Expect(slackClient.Message).To(HaveSuffix("Created 0 of 3 entries."))
+	})
+
+	It("should explain how to use it without any lines", func() {
+		whiteboard.ParseMessageEvent(&emptyEvent)
+		Expect(slackClient.Message).To(HavePrefix("Put one entry on each line after `wb bulk`"))
+		Expect(restClient.PostCalledCount).To(Equal(0))
+	})
+
+	Context("undoing", func() {
+		BeforeEach(func() {
+			whiteboard.ParseMessageEvent(&bulkEvent)
+			whiteboard.ParseMessageEvent(&undoEvent)
+		})
+
+		It("should delete the entries it created", func() {
+			Expect(restClient.DeleteCalledCount).To(Equal(3))
+			Expect(slackClient.Message).To(Equal("Undid your bulk entries, 3 of 3 have been deleted from the whiteboard."))
+			Expect(slackClient.Status).To(Equal(THUMBS_UP))
+		})
+
+		It("should only undo them once", func() {
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(restClient.DeleteCalledCount).To(Equal(3))
+			Expect(slackClient.Message).To(HavePrefix("Hey, you forgot to start new entry."))
+		})
+	})
+
+	Context("undoing after editing an entry", func() {
+		It("should take back the edit before the bulk entries", func() {
+			newInterestingEvent := createMessageEvent("wb i something interesting")
+			setTitleEvent := createMessageEvent("wb t something else")
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			whiteboard.ParseMessageEvent(&bulkEvent)
+			whiteboard.ParseMessageEvent(&setTitleEvent)
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(restClient.DeleteCalledCount).To(Equal(0))
+			Expect(restClient.Request.Item.Title).To(Equal("something interesting"))
+
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(restClient.DeleteCalledCount).To(Equal(3))
+		})
+
+		It("should undo the bulk when it came after the edit", func() {
+			whiteboard.Store.SetEntry("aleung", model.Interesting{Entry: &model.Entry{Id: "42", Title: "something interesting", Author: "Andrew Leung", Date: "2015-01-02", ItemKind: "Interesting", StandupId: 1}})
+			setTitleEvent := createMessageEvent("wb t something else")
+			whiteboard.ParseMessageEvent(&setTitleEvent)
+			whiteboard.ParseMessageEvent(&bulkEvent)
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(restClient.DeleteCalledCount).To(Equal(3))
+
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(restClient.DeleteCalledCount).To(Equal(3))
+			Expect(restClient.Request.Id).To(Equal("42"))
+			Expect(restClient.Request.Item.Title).To(Equal("something interesting"))
+		})
+	})
+
+	Context("undoing when the whiteboard won't delete some entries", func() {
+		It("should keep those for the next undo", func() {
+			whiteboard.ParseMessageEvent(&bulkEvent)
+			restClient.DeleteError = errors.New("boom")
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(slackClient.Message).To(Equal("Undid your bulk entries, 0 of 3 have been deleted from the whiteboard. `wb undo` tries the rest again."))
+			Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+			bulk, ok := whiteboard.Store.GetBulkEntries("whiteboard-sydney", "aleung")
+			Expect(ok).To(BeTrue())
+			Expect(bulk.ItemIds).To(HaveLen(3))
+
+			restClient.DeleteError = nil
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(restClient.DeleteCalledCount).To(Equal(6))
+			Expect(slackClient.Message).To(Equal("Undid your bulk entries, 3 of 3 have been deleted from the whiteboard."))
+			_, ok = whiteboard.Store.GetBulkEntries("whiteboard-sydney", "aleung")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Context("undoing entries made for a named standup", func() {
+		It("should delete them from that standup's whiteboard", func() {
+			registerNamedEvent := createMessageEvent("wb r singapore 12 as sg")
+			namedBulkEvent := createMessageEvent("wb @sg bulk\ni: Something interesting")
+			whiteboard.ParseMessageEvent(&registerNamedEvent)
+			whiteboard.ParseMessageEvent(&namedBulkEvent)
+			restClient.Backend = ""
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(restClient.DeleteCalledCount).To(Equal(1))
+			Expect(restClient.Backend).To(Equal("singapore"))
+		})
+	})
+
+	Context("undoing in another channel", func() {
+		It("should leave the entries alone", func() {
+			whiteboard.ParseMessageEvent(&bulkEvent)
+			otherChannelUndoEvent := createMessageEvent("wb undo")
+			otherChannelUndoEvent.Channel = "whiteboard-melbourne"
+			whiteboard.Store.SetStandup("whiteboard-melbourne", model.Standup{Id: 2, Title: "Melbourne"})
+			whiteboard.ParseMessageEvent(&otherChannelUndoEvent)
+			Expect(restClient.DeleteCalledCount).To(Equal(0))
+			Expect(slackClient.Message).To(HavePrefix("Hey, you forgot to start new entry."))
+		})
+	})
+})
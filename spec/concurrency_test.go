@@ -0,0 +1,79 @@
+package spec
+
+import (
+	"fmt"
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"sync"
+)
+
+var _ = Describe("Concurrent Messages", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		restClient  *MockRestClient
+		store       *MockStore
+	)
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+		store = whiteboard.Store.(*MockStore)
+	})
+
+	Describe("when many users post at the same time", func() {
+		It("should handle every message", func() {
+			var waitGroup sync.WaitGroup
+			dispatcher := NewDispatcher(func(ev *MessageEvent) {
+				defer waitGroup.Done()
+				whiteboard.ParseMessageEvent(ev)
+			})
+			for i := 0; i < 50; i++ {
+				waitGroup.Add(1)
+				go func(i int) {
+					event := createMessageEventWithUser(fmt.Sprintf("wb i title %v", i), fmt.Sprintf("user-%v", i % 10))
+					dispatcher.Dispatch(&event)
+				}(i)
+			}
+			waitGroup.Wait()
+
+			Expect(restClient.PostCalledCount).To(Equal(50))
+			for i := 0; i < 10; i++ {
+				_, ok := store.GetEntry(fmt.Sprintf("user-%v", i))
+				Expect(ok).To(BeTrue())
+			}
+		})
+	})
+
+	Describe("when messages are dispatched", func() {
+		It("should apply each user's messages in order", func() {
+			var waitGroup sync.WaitGroup
+			dispatcher := NewDispatcher(func(ev *MessageEvent) {
+				defer waitGroup.Done()
+				whiteboard.ParseMessageEvent(ev)
+			})
+
+			for _, user := range []string{"aleung", "dlorenc"} {
+				waitGroup.Add(1)
+				event := createMessageEventWithUser("wb i title", user)
+				dispatcher.Dispatch(&event)
+				for i := 0; i < 20; i++ {
+					waitGroup.Add(1)
+					event := createMessageEventWithUser(fmt.Sprintf("wb b body %v", i), user)
+					dispatcher.Dispatch(&event)
+				}
+			}
+			waitGroup.Wait()
+
+			for _, user := range []string{"aleung", "dlorenc"} {
+				entryType, ok := store.GetEntry(user)
+				Expect(ok).To(BeTrue())
+				Expect(entryType.GetEntry().Body).To(Equal("body 19"))
+			}
+			Expect(slackClient.Status).To(Equal(THUMBS_UP + "INTERESTING\n"))
+		})
+	})
+})
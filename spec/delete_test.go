@@ -0,0 +1,192 @@
+package spec
+
+import (
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+)
+
+var _ = Describe("Delete Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		restClient  *MockRestClient
+
+		newInterestingEvent, deleteEvent, deleteItemEvent, confirmEvent, setBodyEvent MessageEvent
+	)
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+
+		restClient.StandupItems = model.StandupItems{}
+		restClient.StandupItems.Helps = []model.Entry{model.Entry{Id: "42", Title: "Help me!", Author: "Andrew Leung", Date: "2015-12-03"}}
+		restClient.StandupItems.Events = []model.Entry{model.Entry{Id: "43", Title: "Another meetup", Author: "Lawrence", Date: "2015-12-03"}}
+		whiteboard.Store.Set(AuthorKey("", "42"), "aleung")
+
+		newInterestingEvent = createMessageEvent("wb i something interesting")
+		deleteEvent = createMessageEvent("wb delete")
+		deleteItemEvent = createMessageEvent("wb delete 42")
+		confirmEvent = createMessageEvent("wb delete yes")
+		setBodyEvent = createMessageEvent("wb b more info")
+	})
+
+	Describe("with no entry started", func() {
+		It("should give a hint on how to start entry", func() {
+			whiteboard.ParseMessageEvent(&deleteEvent)
+			Expect(slackClient.Message).To(Equal("Hey, you forgot to start new entry. Start with one of `wb [face interesting help event] [title]` first!"))
+			Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+		})
+	})
+
+	Describe("confirming with nothing to delete", func() {
+		It("should explain how to delete", func() {
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(slackClient.Message).To(Equal("There's nothing waiting to be deleted. Start with `wb delete` or `wb delete <item_id>` first!"))
+			Expect(restClient.DeleteCalledCount).To(Equal(0))
+		})
+	})
+
+	Context("with an interesting entry started", func() {
+		BeforeEach(func() {
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			whiteboard.ParseMessageEvent(&deleteEvent)
+		})
+
+		It("should ask for confirmation before deleting", func() {
+			Expect(slackClient.Entry.Title).To(Equal("something interesting"))
+			Expect(slackClient.Status).To(Equal("Are you sure you want to delete this entry? Confirm with `wb delete yes`\n\n"))
+			Expect(restClient.DeleteCalledCount).To(Equal(0))
+		})
+
+		Describe("when confirmed", func() {
+			BeforeEach(func() {
+				whiteboard.ParseMessageEvent(&confirmEvent)
+			})
+
+			It("should delete the entry from the whiteboard", func() {
+				Expect(restClient.DeleteCalledCount).To(Equal(1))
+				Expect(restClient.Request.Method).To(Equal("delete"))
+				Expect(restClient.Request.Id).To(Equal("1"))
+				Expect(slackClient.Message).To(Equal("Item 1 has been deleted from the whiteboard."))
+				Expect(slackClient.Status).To(Equal(THUMBS_UP))
+			})
+
+			It("should forget the started entry", func() {
+				whiteboard.ParseMessageEvent(&setBodyEvent)
+				Expect(slackClient.Message).To(Equal("Hey, you forgot to start new entry. Start with one of `wb [face interesting help event] [title]` first!"))
+			})
+
+			It("should not delete twice", func() {
+				whiteboard.ParseMessageEvent(&confirmEvent)
+				Expect(restClient.DeleteCalledCount).To(Equal(1))
+			})
+		})
+
+		Describe("when the whiteboard refuses to delete", func() {
+			It("should tell the user", func() {
+				restClient.DeleteError = &RestError{Kind: AUTH_ERROR, StatusCode: 422}
+				whiteboard.ParseMessageEvent(&confirmEvent)
+				Expect(slackClient.Message).To(Equal("I couldn't delete the item with id: 1. The Whiteboard rejected my session, even after logging in again."))
+				Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+			})
+		})
+	})
+
+	Context("with an item id", func() {
+		It("should delete the item after confirmation", func() {
+			whiteboard.ParseMessageEvent(&deleteItemEvent)
+			Expect(slackClient.Entry.Title).To(Equal("Help me!"))
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(restClient.DeleteCalledCount).To(Equal(1))
+			Expect(restClient.Request.Id).To(Equal("42"))
+		})
+
+		It("should only be confirmed in the channel it was asked in", func() {
+			whiteboard.ParseMessageEvent(&deleteItemEvent)
+			registerEvent := createMessageEvent("wb r 2")
+			registerEvent.Channel = "whiteboard-melbourne"
+			confirmEvent.Channel = "whiteboard-melbourne"
+			whiteboard.ParseMessageEvent(&registerEvent)
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(slackClient.Message).To(Equal("There's nothing waiting to be deleted. Start with `wb delete` or `wb delete <item_id>` first!"))
+			Expect(restClient.DeleteCalledCount).To(Equal(0))
+		})
+
+		It("should only wait a few minutes for the confirmation", func() {
+			whiteboard.ParseMessageEvent(&deleteItemEvent)
+			store := whiteboard.Store.(*MockStore)
+			Expect(store.Expiries).To(HaveKeyWithValue(PendingDeleteKey("whiteboard-sydney", "aleung"), CONFIRMATION_EXPIRY))
+		})
+
+		It("should delete from the standup the delete was asked on", func() {
+			registerNamedEvent := createMessageEvent("wb r singapore 12 as sg")
+			deleteItemEvent.Text = "wb @sg delete 42"
+			whiteboard.Store.Set(AuthorKey("singapore", "42"), "aleung")
+			whiteboard.ParseMessageEvent(&registerNamedEvent)
+			whiteboard.ParseMessageEvent(&deleteItemEvent)
+			restClient.Backend = ""
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(restClient.DeleteCalledCount).To(Equal(1))
+			Expect(restClient.Backend).To(Equal("singapore"))
+		})
+
+		It("should respond when the item can't be found", func() {
+			deleteItemEvent.Text = "wb delete 99"
+			whiteboard.ParseMessageEvent(&deleteItemEvent)
+			Expect(slackClient.Message).To(Equal("I couldn't find an item with id: 99"))
+			Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+		})
+
+		Describe("of an entry by somebody else", func() {
+			BeforeEach(func() {
+				deleteItemEvent.Text = "wb delete 43"
+			})
+
+			It("should not allow the delete", func() {
+				whiteboard.ParseMessageEvent(&deleteItemEvent)
+				whiteboard.ParseMessageEvent(&confirmEvent)
+				Expect(slackClient.Message).To(Equal("There's nothing waiting to be deleted. Start with `wb delete` or `wb delete <item_id>` first!"))
+				Expect(restClient.DeleteCalledCount).To(Equal(0))
+			})
+
+			It("should explain who can delete", func() {
+				whiteboard.ParseMessageEvent(&deleteItemEvent)
+				Expect(slackClient.Message).To(Equal("Only the author of an entry or a workspace admin can delete it!"))
+				Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+			})
+
+			It("should go by who created it rather than their name", func() {
+				whiteboard.Store.Set(AuthorKey("", "43"), "UUserId2")
+				whiteboard.ParseMessageEvent(&deleteItemEvent)
+				Expect(slackClient.Message).To(Equal("Only the author of an entry or a workspace admin can delete it!"))
+
+				deleteItemEvent.Text = "wb delete 42"
+				deleteItemEvent.User = "UUserId2"
+				whiteboard.ParseMessageEvent(&deleteItemEvent)
+				Expect(slackClient.Message).To(Equal("Only the author of an entry or a workspace admin can delete it!"))
+
+				deleteItemEvent.Text = "wb delete 43"
+				confirmEvent.User = "UUserId2"
+				whiteboard.ParseMessageEvent(&deleteItemEvent)
+				whiteboard.ParseMessageEvent(&confirmEvent)
+				Expect(restClient.DeleteCalledCount).To(Equal(1))
+				Expect(restClient.Request.Id).To(Equal("43"))
+				_, ok := whiteboard.Store.Get(AuthorKey("", "43"))
+				Expect(ok).To(BeFalse())
+			})
+
+			It("should allow an admin to delete it", func() {
+				deleteItemEvent.User = "UAdminId"
+				confirmEvent.User = "UAdminId"
+				whiteboard.ParseMessageEvent(&deleteItemEvent)
+				whiteboard.ParseMessageEvent(&confirmEvent)
+				Expect(restClient.DeleteCalledCount).To(Equal(1))
+				Expect(restClient.Request.Id).To(Equal("43"))
+			})
+		})
+	})
+})
@@ -0,0 +1,112 @@
+package spec
+
+import (
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+)
+
+var _ = Describe("Edit Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		restClient  *MockRestClient
+
+		editEvent, setBodyEvent MessageEvent
+	)
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+
+		restClient.StandupItems = model.StandupItems{}
+		restClient.StandupItems.Helps = []model.Entry{model.Entry{Id: "42", Title: "Help me with Go!", Author: "Andrew Leung", Date: "2015-12-03"}}
+		restClient.StandupItems.Interestings = []model.Entry{
+			model.Entry{Id: "43", Title: "Go 1.6 is out", Body: "link", Author: "Mik", Date: "2015-12-03"},
+			model.Entry{Id: "44", Title: "Something else", Body: "link", Author: "Mik", Date: "2015-12-04"}}
+
+		editEvent = createMessageEvent("wb edit 43")
+		setBodyEvent = createMessageEvent("wb b updated body")
+	})
+
+	Describe("with an item id", func() {
+		It("should make the item the started entry", func() {
+			whiteboard.ParseMessageEvent(&editEvent)
+			Expect(slackClient.Entry.Title).To(Equal("Go 1.6 is out"))
+			Expect(slackClient.Status).To(Equal(THUMBS_UP + "_Now go update the details. Need help?_ `wb ?`\n\nINTERESTING\n"))
+		})
+
+		It("should update the item in the whiteboard", func() {
+			whiteboard.ParseMessageEvent(&editEvent)
+			whiteboard.ParseMessageEvent(&setBodyEvent)
+			Expect(restClient.PostCalledCount).To(Equal(1))
+			Expect(restClient.Request.Method).To(Equal("patch"))
+			Expect(restClient.Request.Id).To(Equal("43"))
+			Expect(restClient.Request.Item.Title).To(Equal("Go 1.6 is out"))
+			Expect(restClient.Request.Item.Description).To(Equal("updated body"))
+			Expect(restClient.Request.Item.Author).To(Equal("Mik"))
+			Expect(restClient.Request.Item.Kind).To(Equal("Interesting"))
+			Expect(restClient.Request.Item.StandupId).To(Equal(1))
+			Expect(slackClient.Status).To(Equal(THUMBS_UP + "INTERESTING\n"))
+		})
+	})
+
+	Describe("with search text matching one entry", func() {
+		It("should make the matching item the started entry", func() {
+			editEvent.Text = "wb edit help me"
+			whiteboard.ParseMessageEvent(&editEvent)
+			Expect(slackClient.Entry.Id).To(Equal("42"))
+			Expect(slackClient.Entry.ItemKind).To(Equal("Help"))
+		})
+	})
+
+	Describe("with search text matching several entries", func() {
+		BeforeEach(func() {
+			editEvent.Text = "wb edit go"
+			whiteboard.ParseMessageEvent(&editEvent)
+		})
+
+		It("should list the matching entries", func() {
+			Expect(slackClient.Message).To(Equal("I found a few entries, which one do you want to edit?\n" +
+				"\n`#1` *Help me with Go!* [Andrew Leung] 03 Dec 2015" +
+				"\n`#2` *Go 1.6 is out* [Mik] 03 Dec 2015" +
+				"\n\nPick one with `wb edit #1`"))
+		})
+
+		It("should edit the picked entry", func() {
+			editEvent.Text = "wb edit #2"
+			whiteboard.ParseMessageEvent(&editEvent)
+			whiteboard.ParseMessageEvent(&setBodyEvent)
+			Expect(restClient.Request.Id).To(Equal("43"))
+			Expect(restClient.Request.Item.Description).To(Equal("updated body"))
+		})
+
+		It("should only pick from the entries listed in the same channel", func() {
+			registerEvent := createMessageEvent("wb r 1")
+			registerEvent.Channel = "whiteboard-melbourne"
+			editEvent.Text = "wb edit #2"
+			editEvent.Channel = "whiteboard-melbourne"
+			whiteboard.ParseMessageEvent(&registerEvent)
+			whiteboard.ParseMessageEvent(&editEvent)
+			Expect(slackClient.Message).To(Equal("I couldn't find an entry matching: #2"))
+		})
+
+		It("should not pick an entry out of range", func() {
+			editEvent.Text = "wb edit #3"
+			whiteboard.ParseMessageEvent(&editEvent)
+			Expect(slackClient.Message).To(Equal("I couldn't find an entry matching: #3"))
+		})
+	})
+
+	Describe("with search text matching nothing", func() {
+		It("should respond with not found", func() {
+			editEvent.Text = "wb edit nothing like this"
+			whiteboard.ParseMessageEvent(&editEvent)
+			Expect(slackClient.Message).To(Equal("I couldn't find an entry matching: nothing like this"))
+			Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+		})
+	})
+})
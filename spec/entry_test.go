@@ -72,6 +72,15 @@ var _ = Describe("Entry Integration", func() {
 		})
 	})
 
+	Describe("with interesting keyword and title when the whiteboard is unreachable", func() {
+		It("should say what went wrong", func() {
+			restClient.PostError = &RestError{Kind: NETWORK_ERROR}
+			whiteboard.ParseMessageEvent(&newInterestingWithTitleEvent)
+			Expect(slackClient.Entry.Title).To(Equal("something interesting"))
+			Expect(slackClient.Status).To(Equal(THUMBS_DOWN + "I couldn't reach the Whiteboard, try again in a bit.\n"))
+		})
+	})
+
 	Describe("with help keyword and title", func() {
 		It("should create a new help entry with title", func() {
 			whiteboard.ParseMessageEvent(&newHelpEventTitleEvent)
@@ -214,11 +223,18 @@ var _ = Describe("Entry Integration", func() {
 					Expect(slackClient.Status).To(Equal(THUMBS_UP + "INTERESTING\n"))
 				})
 
+				It("should set a relative date in the standup's time zone", func() {
+					setDateEvent.Text = "wb date next friday"
+					whiteboard.ParseMessageEvent(&setDateEvent)
+					Expect(slackClient.Entry.Date).To(Equal("2015-01-09"))
+					Expect(slackClient.Status).To(Equal(THUMBS_UP + "INTERESTING\n"))
+				})
+
 				It("should not set invalid date and respond with help message", func() {
 					setDateEvent.Text = "wb date 12/01/2015"
 					whiteboard.ParseMessageEvent(&setDateEvent)
 					Expect(slackClient.Entry.Date).To(Equal("2015-01-02"))
-					Expect(slackClient.Status).To(Equal(THUMBS_DOWN + "Date not set, use YYYY-MM-DD as date format\n"))
+					Expect(slackClient.Status).To(Equal(THUMBS_DOWN + "Date not set, use a date like YYYY-MM-DD, 3 Dec, tomorrow or next friday\n"))
 				})
 			})
 		})
@@ -287,6 +303,26 @@ var _ = Describe("Entry Integration", func() {
 		})
 	})
 
+	Context("after the bot restarts", func() {
+		BeforeEach(func() {
+			whiteboard.ParseMessageEvent(&newInterestingWithTitleEvent)
+			whiteboard = NewWhiteboard(slackClient, restClient, MockClock{}, whiteboard.Store)
+		})
+
+		Describe("updating the started entry", func() {
+			It("should update the entry restored from the store", func() {
+				whiteboard.ParseMessageEvent(&setBodyEvent)
+				Expect(slackClient.Entry.Title).To(Equal("something interesting"))
+				Expect(slackClient.Entry.Body).To(Equal("more info"))
+				Expect(slackClient.Status).To(Equal(THUMBS_UP + "INTERESTING\n"))
+				Expect(restClient.PostCalledCount).To(Equal(2))
+				Expect(restClient.Request.Method).To(Equal("patch"))
+				Expect(restClient.Request.Id).To(Equal("1"))
+				Expect(restClient.Request.Item.StandupId).To(Equal(1))
+			})
+		})
+	})
+
 	Context("posting to another standup ID", func() {
 		BeforeEach(func() {
 			registerStandup(whiteboard, 123)
@@ -0,0 +1,135 @@
+package spec
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+)
+
+var _ = Describe("Events API Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		restClient  *MockRestClient
+		server      *httptest.Server
+		timestamp   string
+	)
+
+	post := func(body string, signature string) *http.Response {
+		request, _ := http.NewRequest("POST", server.URL, bytes.NewBufferString(body))
+		request.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		request.Header.Set("X-Slack-Signature", signature)
+		response, err := http.DefaultClient.Do(request)
+		Expect(err).To(BeNil())
+		return response
+	}
+
+	postSigned := func(body string) *http.Response {
+		return post(body, SignSlackRequest("secret", timestamp, []byte(body)))
+	}
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+		slackClient.Message = ""
+
+		timestamp = strconv.FormatInt(MockClock{}.Now().Unix(), 10)
+		server = httptest.NewServer(EventsHandler{SigningSecret: "secret", Clock: MockClock{}, Handle: whiteboard.ParseMessageEvent})
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	Describe("url verification", func() {
+		It("should respond with the challenge", func() {
+			response := postSigned(`{"type": "url_verification", "token": "token", "challenge": "the-challenge"}`)
+			body, _ := ioutil.ReadAll(response.Body)
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(string(body)).To(Equal("the-challenge"))
+		})
+	})
+
+	Describe("with an invalid signature", func() {
+		It("should reject the request", func() {
+			response := post(`{"type": "url_verification", "challenge": "the-challenge"}`, "v0=invalid")
+			Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("with an old timestamp", func() {
+		It("should reject the request", func() {
+			timestamp = strconv.FormatInt(MockClock{}.Now().Unix() - 600, 10)
+			response := postSigned(`{"type": "url_verification", "challenge": "the-challenge"}`)
+			Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+	})
+
+	Describe("message event", func() {
+		It("should handle the message like a RTM message", func() {
+			response := postSigned(`{"type": "event_callback", "event": {"type": "message", "user": "aleung", "text": "wb i something interesting", "channel": "whiteboard-sydney", "ts": "1451606400.000002"}}`)
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(slackClient.Entry.Title).To(Equal("something interesting"))
+			Expect(restClient.PostCalledCount).To(Equal(1))
+		})
+
+		It("should ignore bot messages", func() {
+			postSigned(`{"type": "event_callback", "event": {"type": "message", "subtype": "bot_message", "bot_id": "B1", "text": "wb i something interesting", "channel": "whiteboard-sydney"}}`)
+			Expect(restClient.PostCalledCount).To(Equal(0))
+		})
+	})
+
+	Describe("a message delivered more than once", func() {
+		messageEvent := `{"type": "event_callback", "event": {"type": "message", "user": "aleung", "text": "wb i something interesting", "channel": "whiteboard-sydney", "ts": "1451606400.000002"}}`
+
+		It("should handle it once when it arrives over both RTM and the Events API", func() {
+			server.Close()
+			dispatcher := NewDispatcher(whiteboard.ParseMessageEvent)
+			server = httptest.NewServer(EventsHandler{SigningSecret: "secret", Clock: MockClock{}, Handle: dispatcher.Dispatch})
+
+			rtmEvent := createMessageEvent("wb i something interesting")
+			rtmEvent.Timestamp = "1451606400.000002"
+			dispatcher.Dispatch(&rtmEvent)
+			postSigned(messageEvent)
+
+			done := make(chan struct{})
+			dispatcher.DispatchTask("aleung", func() {
+				close(done)
+			})
+			Eventually(done).Should(BeClosed())
+			Expect(restClient.PostCalledCount).To(Equal(1))
+			Expect(slackClient.PostEntryCalledCount).To(Equal(1))
+		})
+
+		It("should not handle Slack's retries again", func() {
+			postSigned(messageEvent)
+			postSigned(messageEvent)
+			Expect(restClient.PostCalledCount).To(Equal(1))
+		})
+
+		It("should still handle other messages", func() {
+			postSigned(messageEvent)
+			postSigned(`{"type": "event_callback", "event": {"type": "message", "user": "aleung", "text": "wb i something else", "channel": "whiteboard-sydney", "ts": "1451606400.000003"}}`)
+			Expect(restClient.PostCalledCount).To(Equal(2))
+		})
+	})
+
+	Describe("app mention event", func() {
+		It("should handle the mention without the wb keyword", func() {
+			postSigned(`{"type": "event_callback", "event": {"type": "app_mention", "user": "aleung", "text": "<@UBotId> i something interesting", "channel": "whiteboard-sydney"}}`)
+			Expect(slackClient.Entry.Title).To(Equal("something interesting"))
+		})
+
+		It("should handle the mention with the wb keyword", func() {
+			postSigned(`{"type": "event_callback", "event": {"type": "app_mention", "user": "aleung", "text": "<@UBotId> wb h some help", "channel": "whiteboard-sydney"}}`)
+			Expect(slackClient.Entry.Title).To(Equal("some help"))
+			Expect(slackClient.Entry.ItemKind).To(Equal("Help"))
+		})
+	})
+})
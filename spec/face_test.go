@@ -60,6 +60,13 @@ var _ = Describe("Faces Integration", func() {
 					Expect(slackClient.Status).To(Equal(THUMBS_UP + "NEW FACE\n"))
 				})
 
+				It("should set the date of the face entry in the whiteboard", func() {
+					whiteboard.ParseMessageEvent(&setDateEvent)
+					Expect(restClient.PostCalledCount).To(Equal(2))
+					Expect(restClient.Request.Item.Date).To(Equal("2015-12-01"))
+					Expect(slackClient.Entry.Date).To(Equal("2015-12-01"))
+				})
+
 				It("should not update existing face entry in the whiteboard when incorrect keyword", func() {
 					whiteboard.ParseMessageEvent(&setNameEvent)
 					Expect(restClient.PostCalledCount).To(Equal(2))
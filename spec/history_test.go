@@ -0,0 +1,107 @@
+package spec
+
+import (
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+)
+
+var _ = Describe("History Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		restClient  *MockRestClient
+
+		newInterestingEvent, setTitleEvent, setBodyEvent, setAnotherTitleEvent, undoEvent, redoEvent, historyEvent MessageEvent
+	)
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+
+		newInterestingEvent = createMessageEvent("wb i something interesting")
+		setTitleEvent = createMessageEvent("wb t a better title")
+		setBodyEvent = createMessageEvent("wb b more info")
+		setAnotherTitleEvent = createMessageEvent("wb t another title")
+		undoEvent = createMessageEvent("wb undo")
+		redoEvent = createMessageEvent("wb redo")
+		historyEvent = createMessageEvent("wb history")
+	})
+
+	Describe("with no entry started", func() {
+		It("should give a hint on how to start entry", func() {
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(slackClient.Message).To(Equal("Hey, you forgot to start new entry. Start with one of `wb [face interesting help event] [title]` first!"))
+		})
+	})
+
+	Context("with a new entry", func() {
+		BeforeEach(func() {
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+		})
+
+		It("should have nothing to undo or redo", func() {
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(slackClient.Message).To(Equal("There's nothing to undo for this entry."))
+			whiteboard.ParseMessageEvent(&redoEvent)
+			Expect(slackClient.Message).To(Equal("There's nothing to redo for this entry."))
+			Expect(restClient.PostCalledCount).To(Equal(1))
+		})
+
+		It("should have no history", func() {
+			whiteboard.ParseMessageEvent(&historyEvent)
+			Expect(slackClient.Message).To(Equal("*something interesting* hasn't been changed since it was created."))
+		})
+	})
+
+	Context("with an edited entry", func() {
+		BeforeEach(func() {
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			whiteboard.ParseMessageEvent(&setTitleEvent)
+			whiteboard.ParseMessageEvent(&setBodyEvent)
+		})
+
+		It("should undo the last change on the whiteboard", func() {
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(restClient.PostCalledCount).To(Equal(4))
+			Expect(restClient.Request.Method).To(Equal("patch"))
+			Expect(restClient.Request.Item.Title).To(Equal("a better title"))
+			Expect(restClient.Request.Item.Description).To(Equal(""))
+		})
+
+		It("should undo changes one at a time", func() {
+			whiteboard.ParseMessageEvent(&undoEvent)
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(restClient.Request.Item.Title).To(Equal("something interesting"))
+			whiteboard.ParseMessageEvent(&undoEvent)
+			Expect(slackClient.Message).To(Equal("There's nothing to undo for this entry."))
+		})
+
+		It("should redo an undone change", func() {
+			whiteboard.ParseMessageEvent(&undoEvent)
+			whiteboard.ParseMessageEvent(&redoEvent)
+			Expect(restClient.PostCalledCount).To(Equal(5))
+			Expect(restClient.Request.Item.Description).To(Equal("more info"))
+			whiteboard.ParseMessageEvent(&redoEvent)
+			Expect(slackClient.Message).To(Equal("There's nothing to redo for this entry."))
+		})
+
+		It("should forget undone changes once the entry is edited again", func() {
+			whiteboard.ParseMessageEvent(&undoEvent)
+			whiteboard.ParseMessageEvent(&setAnotherTitleEvent)
+			whiteboard.ParseMessageEvent(&redoEvent)
+			Expect(slackClient.Message).To(Equal("There's nothing to redo for this entry."))
+		})
+
+		It("should list the changes with their authors and times", func() {
+			whiteboard.ParseMessageEvent(&undoEvent)
+			whiteboard.ParseMessageEvent(&historyEvent)
+			Expect(slackClient.Message).To(Equal("Changes to *a better title*:\n" +
+				"\n`#1` 02 Jan 2015 11:00 [Andrew Leung] title: something interesting → a better title" +
+				"\n`#2` 02 Jan 2015 11:00 [Andrew Leung] body: _nothing_ → more info _(undone)_" +
+				"\n\nTake back the last change with `wb undo`, or make an undone one again with `wb redo`"))
+		})
+	})
+})
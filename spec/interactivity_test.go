@@ -0,0 +1,194 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+)
+
+var _ = Describe("Entry Card Integration", func() {
+	var (
+		whiteboard     WhiteboardApp
+		slackClient    *MockSlackClient
+		restClient     *MockRestClient
+		server         *httptest.Server
+		responseServer *httptest.Server
+	)
+
+	pressButtonOnMessage := func(message map[string]interface{}, actionId string, itemId string, selectedDate string) *http.Response {
+		payload, _ := json.Marshal(map[string]interface{}{
+			"type":         "block_actions",
+			"response_url": responseServer.URL,
+			"trigger_id":   "trigger",
+			"user":         map[string]string{"id": "aleung"},
+			"channel":      map[string]string{"id": "whiteboard-sydney"},
+			"message":      message,
+			"actions":      []map[string]string{{"action_id": actionId, "block_id": itemId, "selected_date": selectedDate}},
+		})
+		response, err := postSignedForm(server.URL, url.Values{"payload": {string(payload)}}.Encode())
+		Expect(err).To(BeNil())
+		return response
+	}
+
+	pressButton := func(actionId string, itemId string, selectedDate string) *http.Response {
+		return pressButtonOnMessage(map[string]interface{}{"ts": "1420156800.000200"}, actionId, itemId, selectedDate)
+	}
+
+	submitBody := func(itemId string, body string) *http.Response {
+		values := map[string]map[string]map[string]string{ENTRY_BODY_BLOCK: {ENTRY_BODY_BLOCK: {"value": body}}}
+		metadata := ModalMetadata{Channel: "whiteboard-sydney", Entry: itemId, Timestamp: "1420156800.000200"}
+		payload, _ := json.Marshal(map[string]interface{}{
+			"type": "view_submission",
+			"user": map[string]string{"id": "aleung"},
+			"view": map[string]interface{}{"callback_id": EDIT_BODY_CALLBACK, "private_metadata": metadata.String(), "state": map[string]interface{}{"values": values}},
+		})
+		response, err := postSignedForm(server.URL, url.Values{"payload": {string(payload)}}.Encode())
+		Expect(err).To(BeNil())
+		return response
+	}
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+
+		restClient.StandupItems = model.StandupItems{}
+		restClient.StandupItems.Helps = []model.Entry{model.Entry{Id: "42", Title: "Help me!", Author: "Andrew Leung", Date: "2015-12-03"}}
+		restClient.StandupItems.Events = []model.Entry{model.Entry{Id: "43", Title: "Another meetup", Author: "Lawrence", Date: "2015-12-03"}}
+		whiteboard.Store.Set(AuthorKey("", "42"), "aleung")
+
+		responseServer = httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, req *http.Request) {}))
+		server = httptest.NewServer(InteractivityHandler{SigningSecret: "secret", Clock: MockClock{}, Whiteboard: whiteboard})
+	})
+
+	AfterEach(func() {
+		server.Close()
+		responseServer.Close()
+	})
+
+	Describe("updating an entry with commands", func() {
+		It("should update the posted card in place", func() {
+			newInterestingEvent := createMessageEvent("wb i something interesting")
+			setBodyEvent := createMessageEvent("wb b more info")
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			whiteboard.ParseMessageEvent(&setBodyEvent)
+
+			Expect(slackClient.PostEntryCalledCount).To(Equal(1))
+			Expect(slackClient.UpdateEntryCalledCount).To(Equal(1))
+			Expect(slackClient.Timestamp).To(Equal(MOCK_TIMESTAMP))
+			Expect(slackClient.Entry.Body).To(Equal("more info"))
+			Expect(slackClient.Status).To(Equal(THUMBS_UP + "INTERESTING\n"))
+		})
+	})
+
+	Describe("pressing Make public", func() {
+		It("should publish the entry and update the card", func() {
+			response := pressButton(MAKE_PUBLIC_ACTION, "42", "")
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(restClient.Request.Id).To(Equal("42"))
+			Expect(restClient.Request.Item.Public).To(Equal("true"))
+			Expect(slackClient.UpdateEntryCalledCount).To(Equal(1))
+			Expect(slackClient.Timestamp).To(Equal("1420156800.000200"))
+		})
+
+		It("should publish the entry on the whiteboard of the card", func() {
+			registerNamedEvent := createMessageEvent("wb r singapore 12 as sg")
+			whiteboard.ParseMessageEvent(&registerNamedEvent)
+			pressButton(MAKE_PUBLIC_ACTION, "singapore:42", "")
+			Expect(restClient.Request.Id).To(Equal("42"))
+			Expect(restClient.Backend).To(Equal("singapore"))
+
+			pressButton(MAKE_PUBLIC_ACTION, ":42", "")
+			Expect(restClient.Backend).To(Equal(""))
+		})
+
+		It("should leave the presser's own current entry alone", func() {
+			draft := model.Interesting{Entry: &model.Entry{Id: "7", Title: "my own draft", Author: "Andrew Leung", Date: "2015-01-02", ItemKind: "Interesting", StandupId: 1}}
+			whiteboard.Store.SetEntry("aleung", draft)
+			pressButton(MAKE_PUBLIC_ACTION, "42", "")
+			Expect(restClient.Request.Id).To(Equal("42"))
+
+			current, ok := whiteboard.Store.GetEntry("aleung")
+			Expect(ok).To(BeTrue())
+			Expect(current.GetEntry().Id).To(Equal("7"))
+			Expect(current.GetEntry().Public).To(BeFalse())
+		})
+
+		It("should keep the presser's current entry up to date when it's the card's entry", func() {
+			whiteboard.Store.SetEntry("aleung", model.Help{Entry: &model.Entry{Id: "42", Title: "Help me!", Author: "Andrew Leung", Date: "2015-12-03", ItemKind: "Help", StandupId: 1}})
+			pressButton(MAKE_PUBLIC_ACTION, "42", "")
+
+			current, _ := whiteboard.Store.GetEntry("aleung")
+			Expect(current.GetEntry().Public).To(BeTrue())
+		})
+	})
+
+	Describe("pressing Change date", func() {
+		It("should update the date of the entry", func() {
+			pressButton(CHANGE_DATE_ACTION, "42", "2015-12-25")
+			Expect(restClient.Request.Item.Date).To(Equal("2015-12-25"))
+			Expect(slackClient.Entry.Date).To(Equal("2015-12-25"))
+			Expect(slackClient.UpdateEntryCalledCount).To(Equal(1))
+		})
+	})
+
+	Describe("pressing Edit body", func() {
+		It("should open a form filled in with the body on the card without waiting for the whiteboard", func() {
+			entry := model.Entry{Id: "42", Title: "Help me!", Body: "Anyone know Go &amp; &lt;Rust&gt;?", Author: "Andrew Leung", Date: "2015-12-03", ItemKind: "Help"}
+			card, _ := json.Marshal(NewEntryCard(&entry))
+			var attachments interface{}
+			json.Unmarshal(card, &attachments)
+			restClient.GetError = errors.New("whiteboard is down")
+
+			pressButtonOnMessage(map[string]interface{}{"ts": "1420156800.000200", "attachments": attachments}, EDIT_BODY_ACTION, ":42", "")
+			Expect(slackClient.TriggerId).To(Equal("trigger"))
+			modal := slackClient.View.(EntryModal)
+			Expect(modal.CallbackId).To(Equal(EDIT_BODY_CALLBACK))
+			Expect(modal.Blocks[0].Element.InitialValue).To(Equal("Anyone know Go & <Rust>?"))
+			Expect(ParseModalMetadata(modal.PrivateMetadata)).To(Equal(ModalMetadata{Channel: "whiteboard-sydney", Entry: ":42", Timestamp: "1420156800.000200"}))
+			Expect(restClient.PostCalledCount).To(Equal(0))
+		})
+
+		It("should update the body from the form", func() {
+			response := submitBody("42", "Anyone know Go? Pairing after lunch")
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(restClient.Request.Id).To(Equal("42"))
+			Expect(restClient.Request.Item.Description).To(Equal("Anyone know Go? Pairing after lunch"))
+			Expect(slackClient.Entry.Body).To(Equal("Anyone know Go? Pairing after lunch"))
+			Expect(slackClient.UpdateEntryCalledCount).To(Equal(1))
+			Expect(slackClient.Timestamp).To(Equal("1420156800.000200"))
+			_, ok := whiteboard.Store.GetEntry("aleung")
+			Expect(ok).To(BeFalse())
+		})
+	})
+
+	Describe("pressing Delete", func() {
+		It("should delete the entry and replace the card", func() {
+			pressButton(DELETE_ACTION, "42", "")
+			Expect(restClient.DeleteCalledCount).To(Equal(1))
+			Expect(restClient.Request.Id).To(Equal("42"))
+			Expect(slackClient.Message).To(Equal("Item 42 has been deleted from the whiteboard."))
+			Expect(slackClient.Timestamp).To(Equal("1420156800.000200"))
+		})
+
+		It("should not delete someone else's entry", func() {
+			pressButton(DELETE_ACTION, "43", "")
+			Expect(restClient.DeleteCalledCount).To(Equal(0))
+		})
+	})
+
+	Describe("with an invalid signature", func() {
+		It("should reject the request", func() {
+			request, _ := http.NewRequest("POST", server.URL, bytes.NewBufferString("payload={}"))
+			response, _ := http.DefaultClient.Do(request)
+			Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+		})
+	})
+})
@@ -7,30 +7,80 @@ import (
 	"encoding/json"
 	. "github.com/pivotal-sydney/whiteboardbot/app"
 	"github.com/nlopes/slack"
+	"sync"
+	"strings"
+	"sort"
+)
+
+const (
+	MOCK_TIMESTAMP = "1420156800.000100"
+	MOCK_HOST_URL  = "http://localhost:3000"
 )
 
 type MockSlackClient struct {
-	PostMessageCalled bool
-	Message           string
-	Entry 		      *model.Entry
-	Status 			  string
+	PostMessageCalled      bool
+	PostEntryCalledCount   int
+	UpdateEntryCalledCount int
+	Message                string
+	Entry                  *model.Entry
+	Status                 string
+	Timestamp              string
+	TriggerId              string
+	View                   interface{}
+	mutex                  sync.Mutex
 }
 
 func (slackClient *MockSlackClient) PostMessage(message string, channel string, status string) {
+	slackClient.mutex.Lock()
+	defer slackClient.mutex.Unlock()
 	slackClient.PostMessageCalled = true
 	slackClient.Message = message
 	slackClient.Status = status
 }
 
 func (slackClient *MockSlackClient) PostMessageWithMarkdown(message string, channel string, status string) {
+	slackClient.mutex.Lock()
+	defer slackClient.mutex.Unlock()
 	slackClient.PostMessageCalled = true
 	slackClient.Message = message
 	slackClient.Status = status
 }
 
-func (slackClient *MockSlackClient) PostEntry(entry *model.Entry, channel string, status string) {
+func (slackClient *MockSlackClient) PostReply(message string, channel string, status string) {
+	slackClient.PostMessage(message, channel, status)
+}
+
+func (slackClient *MockSlackClient) PostEntry(entry *model.Entry, channel string, status string) (timestamp string) {
+	slackClient.mutex.Lock()
+	defer slackClient.mutex.Unlock()
+	slackClient.PostEntryCalledCount++
+	slackClient.Entry = entry
+	slackClient.Status = status
+	return MOCK_TIMESTAMP
+}
+
+func (slackClient *MockSlackClient) UpdateEntry(entry *model.Entry, channel string, timestamp string, status string) {
+	slackClient.mutex.Lock()
+	defer slackClient.mutex.Unlock()
+	slackClient.UpdateEntryCalledCount++
 	slackClient.Entry = entry
 	slackClient.Status = status
+	slackClient.Timestamp = timestamp
+}
+
+func (slackClient *MockSlackClient) UpdateMessage(message string, channel string, timestamp string) {
+	slackClient.mutex.Lock()
+	defer slackClient.mutex.Unlock()
+	slackClient.Message = message
+	slackClient.Timestamp = timestamp
+}
+
+func (slackClient *MockSlackClient) OpenView(triggerId string, view interface{}) (ok bool) {
+	slackClient.mutex.Lock()
+	defer slackClient.mutex.Unlock()
+	slackClient.TriggerId = triggerId
+	slackClient.View = view
+	return true
 }
 
 func (slackClient *MockSlackClient) GetUserDetails(user string) (slackUser SlackUser) {
@@ -47,6 +97,11 @@ func (slackClient *MockSlackClient) GetUserDetails(user string) (slackUser Slack
 	if slackUser.Username == "" {
 		slackUser.Username = "aleung"
 	}
+
+	if slackUser.Username == "UAdminId" {
+		slackUser.Username = "admin"
+		slackUser.IsAdmin = true
+	}
 	slackUser.Author = "Andrew Leung"
 	slackUser.TimeZone = "Australia/Sydney"
 	return
@@ -73,40 +128,157 @@ func (clock MockClock) Now() time.Time {
 	return time.Date(2015, 1, 2, 0, 0, 0, 0, time.UTC)
 }
 
+type FakeClock struct {
+	CurrentTime time.Time
+	mutex       sync.Mutex
+}
+
+func (clock *FakeClock) Now() time.Time {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	return clock.CurrentTime
+}
+
+func (clock *FakeClock) Advance(duration time.Duration) {
+	clock.mutex.Lock()
+	defer clock.mutex.Unlock()
+	clock.CurrentTime = clock.CurrentTime.Add(duration)
+}
+
 type MockRestClient struct {
-	PostCalledCount int
-	Request         model.WhiteboardRequest
-	StandupItems    model.StandupItems
+	PostCalledCount      int
+	DeleteCalledCount    int
+	PostError            error
+	DeleteError          error
+	GetError             error
+	Backend              string
+	Request              model.WhiteboardRequest
+	StandupItems         model.StandupItems
+	PostRequest          model.PostRequest
+	SendEmailCalledCount int
+	PostsError           error
+	UpdateItems          bool
+	PostDelay            time.Duration
+	mutex                sync.Mutex
+}
+
+func (client *MockRestClient) ForBackend(name string) (RestClient, bool) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.Backend = name
+	return client, name != "unknown"
 }
 
-func (client MockRestClient) GetStandupItems(standupId int) (items model.StandupItems, ok bool) {
+func (client *MockRestClient) HostUrl() string {
+	return MOCK_HOST_URL
+}
+
+func (client *MockRestClient) GetStandupItems(standupId int) (items model.StandupItems, err error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
 	items = client.StandupItems
-	ok = true
+	err = client.GetError
 	return
 }
 
-func (client *MockRestClient) Post(request model.WhiteboardRequest) (itemId string, ok bool) {
+func (client *MockRestClient) Post(request model.WhiteboardRequest) (itemId string, err error) {
+	time.Sleep(client.PostDelay)
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
 	client.PostCalledCount++
 	client.Request = request
-	ok = true
+	if err = client.PostError; err != nil {
+		return
+	}
 	itemId = "1"
+	if len(request.Id) > 0 {
+		itemId = request.Id
+	}
+	if client.UpdateItems {
+		client.updateItem(request)
+	}
+	return
+}
+
+// updateItem changes the item in StandupItems the way the whiteboard would, on copies of the items so whoever read them
+// before keeps what they read.
+func (client *MockRestClient) updateItem(request model.WhiteboardRequest) {
+	items := &client.StandupItems
+	for _, entries := range []*[]model.Entry{&items.Faces, &items.Helps, &items.Interestings, &items.Events} {
+		updated := append([]model.Entry{}, (*entries)...)
+		for i := range updated {
+			if updated[i].Id == request.Id {
+				updated[i].Title, updated[i].Body, updated[i].Date = request.Item.Title, request.Item.Description, request.Item.Date
+				updated[i].Public = request.Item.Public == "true"
+			}
+		}
+		*entries = updated
+	}
+}
+
+func (client *MockRestClient) PostCount() int {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	return client.PostCalledCount
+}
+
+func (client *MockRestClient) Delete(request model.WhiteboardRequest) (err error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.DeleteCalledCount++
+	client.Request = request
+	err = client.DeleteError
 	return
 }
 
-func (*MockRestClient) GetStandup(standupId string) (standup model.Standup, ok bool) {
+func (client *MockRestClient) GetStandup(standupId string) (standup model.Standup, err error) {
+	if err = client.GetError; err != nil {
+		return
+	}
 	id, _ := strconv.Atoi(standupId)
 	standup.Id = id
 	standup.TimeZone = "Australia/Sydney"
 	standup.Title = "Sydney"
-	ok = true
+	return
+}
+
+func (client *MockRestClient) CreatePost(request model.PostRequest) (postId string, err error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.PostRequest = request
+	if err = client.PostsError; err != nil {
+		return
+	}
+	postId = "7"
+	return
+}
+
+func (client *MockRestClient) GetPost(postId string) (post model.Post, err error) {
+	if err = client.GetError; err != nil {
+		return
+	}
+	post = model.Post{Id: postId, Title: "Sydney 2015-01-02", Items: client.StandupItems}
+	return
+}
+
+func (client *MockRestClient) SendPostEmail(request model.PostRequest) (err error) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	client.SendEmailCalledCount++
+	client.PostRequest = request
+	err = client.PostsError
 	return
 }
 
 type MockStore struct {
 	StoreMap map[string]string
+	Expiries map[string]time.Duration
+	mutex    sync.Mutex
 }
 
 func (store *MockStore) Get(key string) (value string, ok bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
 	if store.StoreMap == nil {
 		store.StoreMap = make(map[string]string)
 	}
@@ -115,15 +287,76 @@ func (store *MockStore) Get(key string) (value string, ok bool) {
 }
 
 func (store *MockStore) Set(key string, value string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
 	if store.StoreMap == nil {
 		store.StoreMap = make(map[string]string)
 	}
 	store.StoreMap[key] = value
 }
 
+func (store *MockStore) SetExpiring(key string, value string, expiry time.Duration) {
+	store.Set(key, value)
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.Expiries == nil {
+		store.Expiries = make(map[string]time.Duration)
+	}
+	store.Expiries[key] = expiry
+}
+
+func (store *MockStore) Delete(key string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	delete(store.StoreMap, key)
+}
+
+func (store *MockStore) Keys(prefix string) (keys []string) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	for key := range store.StoreMap {
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return
+}
+
+func (store *MockStore) SetIfMissing(key string, value string, expiry time.Duration) (ok bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	if store.StoreMap == nil {
+		store.StoreMap = make(map[string]string)
+	}
+	if _, found := store.StoreMap[key]; found {
+		return false
+	}
+	store.StoreMap[key] = value
+	if expiry > 0 {
+		if store.Expiries == nil {
+			store.Expiries = make(map[string]time.Duration)
+		}
+		store.Expiries[key] = expiry
+	}
+	return true
+}
+
+func (store *MockStore) Take(key string) (value string, ok bool) {
+	store.mutex.Lock()
+	defer store.mutex.Unlock()
+	value, ok = store.StoreMap[key]
+	delete(store.StoreMap, key)
+	return
+}
+
+func (store *MockStore) SchemaVersion() int {
+	return SCHEMA_VERSION
+}
+
 func (store *MockStore) GetStandup(channel string) (standup model.Standup, ok bool) {
 	var standupJson string
-	standupJson, _ = store.Get(channel)
+	standupJson, _ = store.Get(StandupKey(channel))
 	err := json.Unmarshal([]byte(standupJson), &standup)
 	ok = err == nil
 	return
@@ -131,5 +364,122 @@ func (store *MockStore) GetStandup(channel string) (standup model.Standup, ok bo
 
 func (store *MockStore) SetStandup(channel string, standup model.Standup) {
 	standupJson, _ := json.Marshal(standup)
-	store.Set(channel, string(standupJson))
-}
\ No newline at end of file
+	store.Set(StandupKey(channel), string(standupJson))
+}
+
+func (store *MockStore) SetStandupIfMissing(channel string, standup model.Standup) (ok bool) {
+	standupJson, _ := json.Marshal(standup)
+	return store.SetIfMissing(StandupKey(channel), string(standupJson), 0)
+}
+
+func (store *MockStore) GetNamedStandups(channel string) (standups []model.Standup, ok bool) {
+	for _, key := range store.Keys(NamedStandupKey(channel, "")) {
+		standupJson, _ := store.Get(key)
+		var standup model.Standup
+		if json.Unmarshal([]byte(standupJson), &standup) == nil {
+			standups = append(standups, standup)
+		}
+	}
+	return standups, len(standups) > 0
+}
+
+func (store *MockStore) SetNamedStandup(channel string, standup model.Standup) {
+	standupJson, _ := json.Marshal(standup)
+	store.Set(NamedStandupKey(channel, standup.Alias), string(standupJson))
+}
+
+func (store *MockStore) GetEntry(username string) (entryType model.EntryType, ok bool) {
+	entryJson, ok := store.Get(EntryKey(username))
+	if !ok {
+		return
+	}
+	return model.UnmarshalEntry(entryJson)
+}
+
+func (store *MockStore) SetEntry(username string, entryType model.EntryType) {
+	store.Set(EntryKey(username), model.MarshalEntry(entryType))
+}
+
+func (store *MockStore) GetEntryMessage(backend string, itemId string) (message EntryMessage, ok bool) {
+	messageJson, ok := store.Get(EntryMessageKey(backend, itemId))
+	if !ok {
+		return
+	}
+	ok = json.Unmarshal([]byte(messageJson), &message) == nil
+	return
+}
+
+func (store *MockStore) SetEntryMessage(backend string, itemId string, message EntryMessage) {
+	messageJson, _ := json.Marshal(message)
+	store.SetExpiring(EntryMessageKey(backend, itemId), string(messageJson), ENTRY_EXPIRY)
+}
+
+func (store *MockStore) GetSchedule(channel string, alias string) (schedule model.Schedule, ok bool) {
+	scheduleJson, ok := store.Get(ScheduleKey(channel, alias))
+	if !ok {
+		return
+	}
+	ok = json.Unmarshal([]byte(scheduleJson), &schedule) == nil
+	return
+}
+
+func (store *MockStore) SetSchedule(channel string, alias string, schedule model.Schedule) {
+	scheduleJson, _ := json.Marshal(schedule)
+	store.Set(ScheduleKey(channel, alias), string(scheduleJson))
+}
+
+func (store *MockStore) GetEntryHistory(backend string, itemId string) (history model.EntryHistory, ok bool) {
+	historyJson, ok := store.Get(HistoryKey(backend, itemId))
+	if !ok {
+		return
+	}
+	ok = json.Unmarshal([]byte(historyJson), &history) == nil
+	return
+}
+
+func (store *MockStore) SetEntryHistory(backend string, itemId string, history model.EntryHistory) {
+	historyJson, _ := json.Marshal(history)
+	store.Set(HistoryKey(backend, itemId), string(historyJson))
+}
+
+func (store *MockStore) GetPendingDelete(channel string, username string) (pending PendingDelete, ok bool) {
+	pendingJson, ok := store.Get(PendingDeleteKey(channel, username))
+	if !ok {
+		return
+	}
+	ok = json.Unmarshal([]byte(pendingJson), &pending) == nil
+	return
+}
+
+func (store *MockStore) SetPendingDelete(channel string, username string, pending PendingDelete) {
+	pendingJson, _ := json.Marshal(pending)
+	store.SetExpiring(PendingDeleteKey(channel, username), string(pendingJson), CONFIRMATION_EXPIRY)
+}
+
+func (store *MockStore) GetBulkEntries(channel string, username string) (bulk BulkEntries, ok bool) {
+	bulkJson, ok := store.Get(BulkKey(channel, username))
+	if !ok {
+		return
+	}
+	ok = json.Unmarshal([]byte(bulkJson), &bulk) == nil
+	return
+}
+
+func (store *MockStore) SetBulkEntries(channel string, username string, bulk BulkEntries) {
+	bulkJson, _ := json.Marshal(bulk)
+	store.SetExpiring(BulkKey(channel, username), string(bulkJson), ENTRY_EXPIRY)
+}
+
+func (store *MockStore) GetArchivedPost(channel string) (post ArchivedPost, ok bool) {
+	postJson, ok := store.Get(PostKey(channel))
+	if !ok {
+		return
+	}
+	ok = json.Unmarshal([]byte(postJson), &post) == nil
+	return
+}
+
+func (store *MockStore) SetArchivedPost(channel string, post ArchivedPost) {
+	postJson, _ := json.Marshal(post)
+	store.Set(PostKey(channel), string(postJson))
+}
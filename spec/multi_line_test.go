@@ -0,0 +1,51 @@
+package spec
+
+import (
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+)
+
+var _ = Describe("Multi-line Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		restClient  *MockRestClient
+
+		multiLineEvent, flagsEvent, badDateEvent MessageEvent
+	)
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+
+		multiLineEvent = createMessageEvent("wb i Something interesting\nlonger body text\nwith a link")
+		flagsEvent = createMessageEvent("wb e Meetup --date 2015-12-01 --public\nAt the office")
+		badDateEvent = createMessageEvent("wb e Meetup --date someday")
+	})
+
+	It("should create the entry with a title and a body", func() {
+		whiteboard.ParseMessageEvent(&multiLineEvent)
+		Expect(restClient.Request.Item.Title).To(Equal("Something interesting"))
+		Expect(restClient.Request.Item.Description).To(Equal("longer body text\nwith a link"))
+		Expect(slackClient.Entry.Body).To(Equal("longer body text\nwith a link"))
+	})
+
+	It("should set the details given as flags", func() {
+		whiteboard.ParseMessageEvent(&flagsEvent)
+		Expect(restClient.PostCalledCount).To(Equal(1))
+		Expect(restClient.Request.Item.Title).To(Equal("Meetup"))
+		Expect(restClient.Request.Item.Date).To(Equal("2015-12-01"))
+		Expect(restClient.Request.Item.Public).To(Equal("true"))
+		Expect(restClient.Request.Item.Description).To(Equal("At the office"))
+	})
+
+	It("should not create the entry with a date it doesn't understand", func() {
+		whiteboard.ParseMessageEvent(&badDateEvent)
+		Expect(restClient.PostCalledCount).To(Equal(0))
+		Expect(slackClient.Message).To(Equal("I don't understand the date: someday\nUse a date like YYYY-MM-DD, 3 Dec, tomorrow or next friday"))
+		Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+	})
+})
@@ -0,0 +1,159 @@
+package spec
+
+import (
+	"encoding/json"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+)
+
+var _ = Describe("New Entry Form Integration", func() {
+	var (
+		whiteboard          WhiteboardApp
+		slackClient         *MockSlackClient
+		restClient          *MockRestClient
+		slashServer         *httptest.Server
+		interactivityServer *httptest.Server
+		responseServer      *httptest.Server
+	)
+
+	submitFormWithMetadata := func(privateMetadata string, kind string, title string, body string, date string) map[string]interface{} {
+		values := map[string]map[string]map[string]interface{}{
+			ENTRY_KIND_BLOCK:  {ENTRY_KIND_BLOCK: {"selected_option": map[string]string{"value": kind}}},
+			ENTRY_TITLE_BLOCK: {ENTRY_TITLE_BLOCK: {"value": title}},
+			ENTRY_BODY_BLOCK:  {ENTRY_BODY_BLOCK: {"value": body}},
+			ENTRY_DATE_BLOCK:  {ENTRY_DATE_BLOCK: {"selected_date": date}},
+		}
+		payload, _ := json.Marshal(map[string]interface{}{
+			"type": "view_submission",
+			"user": map[string]string{"id": "aleung"},
+			"view": map[string]interface{}{"callback_id": NEW_ENTRY_CALLBACK, "private_metadata": privateMetadata, "state": map[string]interface{}{"values": values}},
+		})
+		response, err := postSignedForm(interactivityServer.URL, url.Values{"payload": {string(payload)}}.Encode())
+		Expect(err).To(BeNil())
+		Expect(response.StatusCode).To(Equal(http.StatusOK))
+
+		var result map[string]interface{}
+		responseBody, _ := ioutil.ReadAll(response.Body)
+		json.Unmarshal(responseBody, &result)
+		return result
+	}
+
+	submitForm := func(kind string, title string, body string, date string) map[string]interface{} {
+		return submitFormWithMetadata(ModalMetadata{Channel: "whiteboard-sydney"}.String(), kind, title, body, date)
+	}
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+
+		responseServer = httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, req *http.Request) {}))
+		slashServer = httptest.NewServer(SlashCommandHandler{SigningSecret: "secret", Clock: MockClock{}, Whiteboard: whiteboard})
+		interactivityServer = httptest.NewServer(InteractivityHandler{SigningSecret: "secret", Clock: MockClock{}, Whiteboard: whiteboard})
+	})
+
+	AfterEach(func() {
+		slashServer.Close()
+		interactivityServer.Close()
+		responseServer.Close()
+	})
+
+	Describe("opening the form", func() {
+		It("should open the form from the slash command", func() {
+			form := url.Values{"command": {"/wb"}, "text": {"new"}, "user_id": {"aleung"}, "channel_id": {"whiteboard-sydney"}, "response_url": {responseServer.URL}, "trigger_id": {"trigger"}}
+			postSignedForm(slashServer.URL, form.Encode())
+			Expect(slackClient.TriggerId).To(Equal("trigger"))
+			Expect(ParseModalMetadata(slackClient.View.(EntryModal).PrivateMetadata)).To(Equal(ModalMetadata{Channel: "whiteboard-sydney"}))
+		})
+
+		It("should remember the standup picked for the form", func() {
+			form := url.Values{"command": {"/wb"}, "text": {"@sg new"}, "user_id": {"aleung"}, "channel_id": {"whiteboard-sydney"}, "response_url": {responseServer.URL}, "trigger_id": {"trigger"}}
+			registerNamedEvent := createMessageEvent("wb r singapore 12 as sg")
+			whiteboard.ParseMessageEvent(&registerNamedEvent)
+			postSignedForm(slashServer.URL, form.Encode())
+			Expect(ParseModalMetadata(slackClient.View.(EntryModal).PrivateMetadata)).To(Equal(ModalMetadata{Channel: "whiteboard-sydney", StandupName: "sg"}))
+		})
+
+		It("should explain the form needs the slash command", func() {
+			newEvent := createMessageEvent("wb new")
+			whiteboard.ParseMessageEvent(&newEvent)
+			Expect(slackClient.Message).To(Equal("I can only open the new entry form from the slash command, try `/wb new`"))
+			Expect(slackClient.View).To(BeNil())
+		})
+	})
+
+	Describe("submitting the form", func() {
+		It("should create the whole entry at once", func() {
+			result := submitForm("interestings", "something interesting", "more info", "2015-12-25")
+			Expect(result).To(BeNil())
+			Expect(restClient.PostCalledCount).To(Equal(1))
+			Expect(restClient.Request.Item.Kind).To(Equal("Interesting"))
+			Expect(restClient.Request.Item.Description).To(Equal("more info"))
+			Expect(restClient.Request.Item.Date).To(Equal("2015-12-25"))
+			Expect(slackClient.Entry.Title).To(Equal("something interesting"))
+		})
+
+		It("should create the entry on the standup picked for the form", func() {
+			registerNamedEvent := createMessageEvent("wb r singapore 12 as sg")
+			whiteboard.ParseMessageEvent(&registerNamedEvent)
+			submitFormWithMetadata(ModalMetadata{Channel: "whiteboard-sydney", StandupName: "sg"}.String(), "helps", "Help me", "", "")
+			Expect(restClient.Backend).To(Equal("singapore"))
+			Expect(restClient.Request.Item.StandupId).To(Equal(12))
+		})
+
+		It("should still read forms that only kept the channel", func() {
+			submitFormWithMetadata("whiteboard-sydney", "helps", "Help me", "", "")
+			Expect(restClient.PostCalledCount).To(Equal(1))
+			Expect(restClient.Request.Item.StandupId).To(Equal(1))
+		})
+
+		It("should default the date to today", func() {
+			submitForm("faces", "Dariusz", "", "")
+			Expect(restClient.Request.Item.Kind).To(Equal("New face"))
+			Expect(restClient.Request.Item.Date).To(Equal("2015-01-02"))
+		})
+
+		It("should return validation errors to the form", func() {
+			result := submitForm("faces", "", "a body", "")
+			Expect(result["response_action"]).To(Equal("errors"))
+			Expect(result["errors"]).To(Equal(map[string]interface{}{
+				ENTRY_TITLE_BLOCK: "Oi! The title/name can't be empty!",
+				ENTRY_BODY_BLOCK:  "Face does not have a body!",
+			}))
+			Expect(restClient.PostCalledCount).To(Equal(0))
+		})
+
+		It("should answer Slack before posting the entry to the whiteboard", func() {
+			interactivityServer.Close()
+			dispatcher := NewDispatcher(whiteboard.ParseMessageEvent)
+			interactivityServer = httptest.NewServer(InteractivityHandler{SigningSecret: "secret", Clock: MockClock{}, Whiteboard: whiteboard, Dispatcher: dispatcher})
+			release := make(chan struct{})
+			dispatcher.DispatchTask("aleung", func() {
+				<-release
+			})
+
+			result := submitForm("interestings", "something interesting", "", "")
+			Expect(result).To(BeNil())
+			Expect(restClient.PostCalledCount).To(Equal(0))
+
+			done := make(chan struct{})
+			dispatcher.DispatchTask("aleung", func() {
+				close(done)
+			})
+			close(release)
+			Eventually(done).Should(BeClosed())
+			Expect(restClient.PostCalledCount).To(Equal(1))
+			Expect(restClient.Request.Item.Title).To(Equal("something interesting"))
+		})
+
+		It("should require a kind", func() {
+			result := submitForm("", "Something", "", "")
+			Expect(result["errors"]).To(HaveKeyWithValue(ENTRY_KIND_BLOCK, "Pick what kind of entry this is"))
+		})
+	})
+})
@@ -0,0 +1,153 @@
+package spec
+
+import (
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+)
+
+var _ = Describe("Posts Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		restClient  *MockRestClient
+
+		archiveEvent, archiveWithTitleEvent, emailEvent, confirmEvent MessageEvent
+	)
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+
+		restClient.StandupItems = model.StandupItems{}
+		restClient.StandupItems.Helps = []model.Entry{model.Entry{Id: "42", Title: "Help me!", Author: "Andrew Leung", Date: "2015-01-02"}}
+
+		archiveEvent = createMessageEvent("wb archive")
+		archiveWithTitleEvent = createMessageEvent("wb archive Friday standup")
+		emailEvent = createMessageEvent("wb email")
+		confirmEvent = createMessageEvent("wb email yes")
+	})
+
+	Describe("archiving the standup", func() {
+		It("should create a post named after the standup and today's date", func() {
+			whiteboard.ParseMessageEvent(&archiveEvent)
+			Expect(restClient.PostRequest.Post.StandupId).To(Equal(1))
+			Expect(restClient.PostRequest.Post.Title).To(Equal("Sydney 2015-01-02"))
+			Expect(slackClient.Message).To(Equal("Today's entries have been archived into post 7: Sydney 2015-01-02\nPreview the email with `wb email`"))
+			Expect(slackClient.Status).To(Equal(THUMBS_UP))
+		})
+
+		It("should use the title that follows", func() {
+			whiteboard.ParseMessageEvent(&archiveWithTitleEvent)
+			Expect(restClient.PostRequest.Post.Title).To(Equal("Friday standup"))
+		})
+
+		It("should not archive an empty standup", func() {
+			restClient.StandupItems = model.StandupItems{}
+			whiteboard.ParseMessageEvent(&archiveEvent)
+			Expect(restClient.PostRequest.Post.Title).To(BeEmpty())
+			Expect(slackClient.Message).To(Equal("Hey, there's no entries in today's standup yet, why not add some?"))
+		})
+
+		It("should explain when the whiteboard fails", func() {
+			restClient.PostsError = &RestError{Kind: NETWORK_ERROR}
+			whiteboard.ParseMessageEvent(&archiveEvent)
+			Expect(slackClient.Message).To(Equal("I couldn't archive the standup. I couldn't reach the Whiteboard, try again in a bit."))
+			Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+		})
+	})
+
+	Describe("emailing without a post", func() {
+		It("should ask to archive first", func() {
+			whiteboard.ParseMessageEvent(&emailEvent)
+			Expect(slackClient.Message).To(Equal("There's no post to email yet. Archive today's entries with `wb archive` first!"))
+		})
+
+		It("should have nothing to confirm", func() {
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(slackClient.Message).To(Equal("There's no email waiting to be sent. Preview it with `wb email` first!"))
+			Expect(restClient.SendEmailCalledCount).To(Equal(0))
+		})
+	})
+
+	Context("with a standup archived from a named standup", func() {
+		BeforeEach(func() {
+			registerNamedEvent := createMessageEvent("wb r singapore 12 as sg")
+			namedArchiveEvent := createMessageEvent("wb @sg archive")
+			whiteboard.ParseMessageEvent(&registerNamedEvent)
+			whiteboard.ParseMessageEvent(&namedArchiveEvent)
+			restClient.Backend = ""
+		})
+
+		It("should preview and send the email from that standup", func() {
+			whiteboard.ParseMessageEvent(&emailEvent)
+			Expect(restClient.Backend).To(Equal("singapore"))
+			restClient.Backend = ""
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(restClient.SendEmailCalledCount).To(Equal(1))
+			Expect(restClient.Backend).To(Equal("singapore"))
+		})
+	})
+
+	Context("with an archived standup", func() {
+		BeforeEach(func() {
+			whiteboard.ParseMessageEvent(&archiveEvent)
+			whiteboard.ParseMessageEvent(&emailEvent)
+		})
+
+		It("should preview the email before sending it", func() {
+			Expect(slackClient.Message).To(HavePrefix("Here's a preview of the email:\n\n*Sydney 2015-01-02*\n"))
+			Expect(slackClient.Message).To(ContainSubstring("*Help me!*"))
+			Expect(slackClient.Message).To(HaveSuffix("Send it with `wb email yes`"))
+			Expect(restClient.SendEmailCalledCount).To(Equal(0))
+		})
+
+		It("should send the email once confirmed", func() {
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(restClient.SendEmailCalledCount).To(Equal(1))
+			Expect(restClient.PostRequest.Id).To(Equal("7"))
+			Expect(slackClient.Message).To(Equal("The email for post 7 has been sent!"))
+			Expect(slackClient.Status).To(Equal(THUMBS_UP))
+		})
+
+		It("should not send the email twice", func() {
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(restClient.SendEmailCalledCount).To(Equal(1))
+		})
+
+		It("should not send the email twice when two people confirm it", func() {
+			otherEmailEvent := createMessageEventWithUser("wb email", "dmitri")
+			otherConfirmEvent := createMessageEventWithUser("wb email yes", "dmitri")
+			whiteboard.ParseMessageEvent(&otherEmailEvent)
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			whiteboard.ParseMessageEvent(&otherConfirmEvent)
+			Expect(restClient.SendEmailCalledCount).To(Equal(1))
+			Expect(slackClient.Message).To(Equal("There's no email waiting to be sent. Preview it with `wb email` first!"))
+		})
+
+		It("should only send the email for the channel's latest post", func() {
+			whiteboard.Store.SetArchivedPost("whiteboard-sydney", ArchivedPost{PostId: "8", Standup: model.Standup{Id: 1}})
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(restClient.SendEmailCalledCount).To(Equal(0))
+		})
+
+		It("should only wait a while for the confirmation", func() {
+			store := whiteboard.Store.(*MockStore)
+			Expect(store.Expiries).To(HaveKeyWithValue(PendingEmailKey("whiteboard-sydney", "7"), CONFIRMATION_EXPIRY))
+		})
+
+		It("should keep the post when sending fails", func() {
+			restClient.PostsError = &RestError{Kind: AUTH_ERROR}
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(slackClient.Message).To(Equal("I couldn't send the email for post 7. The Whiteboard rejected my session, even after logging in again."))
+			restClient.PostsError = nil
+			whiteboard.ParseMessageEvent(&emailEvent)
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(restClient.SendEmailCalledCount).To(Equal(2))
+		})
+	})
+})
@@ -46,6 +46,75 @@ var _ = Describe("Present Integration", func() {
 				whiteboard.ParseMessageEvent(&presentEvent)
 				Expect(slackClient.Message).To(Equal(restClient.StandupItems.String()))
 			})
+
+			It("should leave out entries dated before today", func() {
+				restClient.StandupItems.Helps = append(restClient.StandupItems.Helps, model.Entry{Title: "Help me yesterday!", Author: "Lawrence", Date: "2015-01-01"})
+				whiteboard.ParseMessageEvent(&presentEvent)
+				Expect(slackClient.Message).To(ContainSubstring("Help me!"))
+				Expect(slackClient.Message).NotTo(ContainSubstring("Help me yesterday!"))
+			})
+
+			It("should only display the chosen sections, in the order given", func() {
+				presentSectionsEvent := createMessageEvent("wb p i helps")
+				whiteboard.ParseMessageEvent(&presentSectionsEvent)
+				Expect(slackClient.Message).To(Equal(restClient.StandupItems.Present([]string{model.INTERESTINGS_SECTION, model.HELPS_SECTION})))
+				Expect(slackClient.Message).NotTo(ContainSubstring("NEW FACES"))
+			})
+
+			It("should combine sections with the number of days", func() {
+				restClient.StandupItems.Helps = []model.Entry{model.Entry{Title: "Help me!", Author: "Lawrence", Date: "2015-01-03"}, model.Entry{Title: "Help me later!", Author: "Lawrence", Date: "2015-01-10"}}
+				presentSectionsEvent := createMessageEvent("wb p helps 2")
+				whiteboard.ParseMessageEvent(&presentSectionsEvent)
+				Expect(slackClient.Message).To(ContainSubstring("Help me!"))
+				Expect(slackClient.Message).NotTo(ContainSubstring("Help me later!"))
+				Expect(slackClient.Message).NotTo(ContainSubstring("EVENTS"))
+			})
+
+			It("should leave out empty sections", func() {
+				restClient.StandupItems.Events = nil
+				whiteboard.ParseMessageEvent(&presentEvent)
+				Expect(slackClient.Message).NotTo(ContainSubstring("EVENTS"))
+			})
+
+			It("should say when there's nothing in the chosen sections", func() {
+				restClient.StandupItems.Events = nil
+				presentSectionsEvent := createMessageEvent("wb p events")
+				whiteboard.ParseMessageEvent(&presentSectionsEvent)
+				Expect(slackClient.Message).To(Equal("Hey, there's no entries to present for that, try `wb present` to see them all."))
+			})
+
+			It("should explain a filter it doesn't know", func() {
+				presentSectionsEvent := createMessageEvent("wb p lunch")
+				whiteboard.ParseMessageEvent(&presentSectionsEvent)
+				Expect(slackClient.Message).To(Equal("I don't understand the date: lunch\nTry sections like `helps`, a number of days, or dates like `this week` or `2015-12-01..2015-12-05`"))
+				Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+			})
+
+			It("should only display entries within a range of dates", func() {
+				restClient.StandupItems.Helps = append(restClient.StandupItems.Helps, model.Entry{Title: "Help me later!", Author: "Lawrence", Date: "2015-12-30"})
+				presentRangeEvent := createMessageEvent("wb p helps 2015-12-01..2015-12-05")
+				whiteboard.ParseMessageEvent(&presentRangeEvent)
+				Expect(slackClient.Message).To(ContainSubstring("Help me!"))
+				Expect(slackClient.Message).NotTo(ContainSubstring("Help me later!"))
+				Expect(slackClient.Message).NotTo(ContainSubstring("EVENTS"))
+			})
+
+			It("should display this week's entries", func() {
+				restClient.StandupItems.Helps = []model.Entry{model.Entry{Title: "Help me!", Author: "Lawrence", Date: "2015-01-04"}, model.Entry{Title: "Help me later!", Author: "Lawrence", Date: "2015-01-05"}}
+				presentRangeEvent := createMessageEvent("wb p this week")
+				whiteboard.ParseMessageEvent(&presentRangeEvent)
+				Expect(slackClient.Message).To(ContainSubstring("Help me!"))
+				Expect(slackClient.Message).NotTo(ContainSubstring("Help me later!"))
+			})
+
+			It("should present in the channel's order", func() {
+				orderEvent := createMessageEvent("wb p order events i")
+				whiteboard.ParseMessageEvent(&orderEvent)
+				Expect(slackClient.Message).To(Equal("Standup is presented in this order: events, interestings, faces, helps"))
+
+				whiteboard.ParseMessageEvent(&presentEvent)
+				Expect(slackClient.Message).To(Equal(restClient.StandupItems.Present([]string{model.EVENTS_SECTION, model.INTERESTINGS_SECTION, model.FACES_SECTION, model.HELPS_SECTION})))
+			})
 		})
 	})
 })
\ No newline at end of file
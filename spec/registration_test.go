@@ -37,5 +37,29 @@ var _ = Describe("Standup Registration", func() {
 				Expect(slackClient.Status).To(Equal(THUMBS_UP))
 			})
 		})
+
+		Describe("with a whiteboard name and standup id", func() {
+			It("should use that whiteboard for the channel", func() {
+				restClient := whiteboard.RestClient.(*MockRestClient)
+				singaporeEvent := createMessageEvent("wb r singapore 3")
+				newInterestingEvent := createMessageEvent("wb i something interesting")
+				whiteboard.ParseMessageEvent(&singaporeEvent)
+				Expect(slackClient.Message).To(Equal("Standup Sydney has been registered! You can now start creating Whiteboard entries!"))
+
+				restClient.Backend = ""
+				whiteboard.ParseMessageEvent(&newInterestingEvent)
+				Expect(restClient.Backend).To(Equal("singapore"))
+				Expect(restClient.Request.Item.StandupId).To(Equal(3))
+			})
+		})
+
+		Describe("with an unknown whiteboard name", func() {
+			It("should not register the channel", func() {
+				unknownEvent := createMessageEvent("wb r unknown 3")
+				whiteboard.ParseMessageEvent(&unknownEvent)
+				Expect(slackClient.Message).To(Equal("I don't know a whiteboard called: unknown"))
+				Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+			})
+		})
 	})
 })
\ No newline at end of file
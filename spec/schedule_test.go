@@ -0,0 +1,93 @@
+package spec
+
+import (
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+)
+
+var _ = Describe("Schedule Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+
+		scheduleEvent, showEvent, offEvent, badEvent MessageEvent
+	)
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+
+		scheduleEvent = createMessageEvent("wb schedule weekdays 09:05")
+		showEvent = createMessageEvent("wb schedule")
+		offEvent = createMessageEvent("wb schedule off")
+		badEvent = createMessageEvent("wb schedule weekdays")
+	})
+
+	It("should say there's no schedule yet", func() {
+		whiteboard.ParseMessageEvent(&showEvent)
+		Expect(slackClient.Message).To(Equal("There's no schedule for this channel yet. Set one like this: `wb schedule weekdays 09:05`"))
+	})
+
+	It("should schedule the standup in its time zone", func() {
+		whiteboard.ParseMessageEvent(&scheduleEvent)
+		Expect(slackClient.Message).To(Equal("Standup Sydney will be presented weekdays at 09:05, with a reminder 10 minutes before (Australia/Sydney time)."))
+		Expect(slackClient.Status).To(Equal(THUMBS_UP))
+		Expect(whiteboard.ScheduledChannels()).To(Equal([]string{"whiteboard-sydney"}))
+
+		whiteboard.ParseMessageEvent(&showEvent)
+		Expect(slackClient.Message).To(Equal("Standup Sydney is presented weekdays at 09:05, with a reminder 10 minutes before."))
+	})
+
+	It("should only list the channel once", func() {
+		whiteboard.ParseMessageEvent(&scheduleEvent)
+		whiteboard.ParseMessageEvent(&scheduleEvent)
+		Expect(whiteboard.ScheduledChannels()).To(Equal([]string{"whiteboard-sydney"}))
+	})
+
+	It("should find the scheduled channels from the schedules, skipping keys it can't read", func() {
+		whiteboard.ParseMessageEvent(&scheduleEvent)
+		whiteboard.Store.Set(SCHEDULE_KEY_PREFIX + "whiteboard-melbourne:", "{}")
+		whiteboard.Store.Set(SCHEDULE_KEY_PREFIX + "broken", "{}")
+		Expect(whiteboard.ScheduledChannels()).To(Equal([]string{"whiteboard-melbourne", "whiteboard-sydney"}))
+	})
+
+	It("should turn the schedule off", func() {
+		whiteboard.ParseMessageEvent(&scheduleEvent)
+		whiteboard.ParseMessageEvent(&offEvent)
+		Expect(slackClient.Message).To(Equal("Standup Sydney won't be presented automatically any more."))
+		Expect(whiteboard.ScheduledChannels()).To(BeEmpty())
+	})
+
+	It("should keep a schedule for each of the channel's standups", func() {
+		registerNamedEvent := createMessageEvent("wb r singapore 12 as sg")
+		namedScheduleEvent := createMessageEvent("wb @sg schedule fri 10:00")
+		namedShowEvent := createMessageEvent("wb @sg schedule")
+		namedOffEvent := createMessageEvent("wb @sg schedule off")
+		whiteboard.ParseMessageEvent(&registerNamedEvent)
+		whiteboard.ParseMessageEvent(&namedShowEvent)
+		Expect(slackClient.Message).To(Equal("There's no schedule for standup Sydney yet. Set one like this: `wb @sg schedule weekdays 09:05`"))
+
+		whiteboard.ParseMessageEvent(&scheduleEvent)
+		whiteboard.ParseMessageEvent(&namedScheduleEvent)
+		Expect(whiteboard.ScheduledChannels()).To(Equal([]string{"whiteboard-sydney"}))
+		scheduled := whiteboard.ScheduledStandups("whiteboard-sydney")
+		Expect(scheduled).To(HaveLen(2))
+		Expect(scheduled[0].Standup.Id).To(Equal(1))
+		Expect(scheduled[0].Schedule.String()).To(Equal("weekdays at 09:05, with a reminder 10 minutes before"))
+		Expect(scheduled[1].Standup.Id).To(Equal(12))
+		Expect(scheduled[1].Schedule.String()).To(Equal("on Fri at 10:00, with a reminder 10 minutes before"))
+
+		whiteboard.ParseMessageEvent(&offEvent)
+		Expect(whiteboard.ScheduledChannels()).To(Equal([]string{"whiteboard-sydney"}))
+		whiteboard.ParseMessageEvent(&namedOffEvent)
+		Expect(whiteboard.ScheduledChannels()).To(BeEmpty())
+	})
+
+	It("should explain a schedule it can't read", func() {
+		whiteboard.ParseMessageEvent(&badEvent)
+		Expect(slackClient.Message).To(Equal("I need a time for the standup, like 09:05\nLike this: `wb schedule weekdays 09:05 remind 10`"))
+		Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+	})
+})
@@ -0,0 +1,135 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"time"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+)
+
+var _ = Describe("Slash Command Integration", func() {
+	var (
+		whiteboard     WhiteboardApp
+		slackClient    *MockSlackClient
+		restClient     *MockRestClient
+		server         *httptest.Server
+		responseServer *httptest.Server
+		responses      []map[string]string
+	)
+
+	slashCommand := func(text string) *http.Response {
+		form := url.Values{"command": {"/wb"}, "text": {text}, "user_id": {"aleung"}, "channel_id": {"whiteboard-sydney"}, "response_url": {responseServer.URL}}
+		body := form.Encode()
+		timestamp := strconv.FormatInt(MockClock{}.Now().Unix(), 10)
+		request, _ := http.NewRequest("POST", server.URL, bytes.NewBufferString(body))
+		request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+		request.Header.Set("X-Slack-Request-Timestamp", timestamp)
+		request.Header.Set("X-Slack-Signature", SignSlackRequest("secret", timestamp, []byte(body)))
+		response, err := http.DefaultClient.Do(request)
+		Expect(err).To(BeNil())
+		return response
+	}
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+		slackClient.Message = ""
+		slackClient.PostMessageCalled = false
+
+		responses = nil
+		responseServer = httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, req *http.Request) {
+			var response map[string]string
+			body, _ := ioutil.ReadAll(req.Body)
+			json.Unmarshal(body, &response)
+			responses = append(responses, response)
+		}))
+		server = httptest.NewServer(SlashCommandHandler{SigningSecret: "secret", Clock: MockClock{}, Whiteboard: whiteboard})
+	})
+
+	AfterEach(func() {
+		server.Close()
+		responseServer.Close()
+	})
+
+	Describe("creating an entry", func() {
+		It("should post the entry publicly", func() {
+			response := slashCommand("i something interesting")
+			Expect(response.StatusCode).To(Equal(http.StatusOK))
+			Expect(slackClient.Entry.Title).To(Equal("something interesting"))
+			Expect(slackClient.Status).To(Equal(THUMBS_UP + "_Now go update the details. Need help?_ `wb ?`\n\nINTERESTING\n"))
+			Expect(restClient.PostCalledCount).To(Equal(1))
+			Expect(responses).To(BeEmpty())
+		})
+	})
+
+	Describe("asking for usage", func() {
+		It("should respond with usage only to the requester", func() {
+			slashCommand("")
+			Expect(responses).To(HaveLen(1))
+			Expect(responses[0]["response_type"]).To(Equal("ephemeral"))
+			Expect(responses[0]["text"]).To(Equal(USAGE))
+			Expect(slackClient.PostMessageCalled).To(BeFalse())
+		})
+	})
+
+	Describe("with an error", func() {
+		It("should respond with the error only to the requester", func() {
+			slashCommand("b more info")
+			Expect(responses).To(HaveLen(1))
+			Expect(responses[0]["response_type"]).To(Equal("ephemeral"))
+			Expect(responses[0]["text"]).To(Equal(THUMBS_DOWN + "Hey, you forgot to start new entry. Start with one of `wb [face interesting help event] [title]` first!"))
+			Expect(slackClient.PostMessageCalled).To(BeFalse())
+		})
+
+		It("should respond with an invalid date only to the requester", func() {
+			slashCommand("i something interesting")
+			slashCommand("d tomorrowish")
+			Expect(responses).To(HaveLen(1))
+			Expect(responses[0]["text"]).To(HavePrefix(THUMBS_DOWN + "Date not set"))
+		})
+	})
+
+	Describe("with an unknown command", func() {
+		It("should respond only to the requester", func() {
+			slashCommand("hello")
+			Expect(responses).To(HaveLen(1))
+			Expect(responses[0]["text"]).To(Equal("aleung no you hello"))
+		})
+	})
+
+	Describe("with a response_url that doesn't answer", func() {
+		It("should give up on the reply", func() {
+			release := make(chan struct{})
+			stuckServer := httptest.NewServer(http.HandlerFunc(func(responseWriter http.ResponseWriter, req *http.Request) {
+				<-release
+			}))
+			defer stuckServer.Close()
+			defer close(release)
+
+			slashClient := &SlashCommandClient{SlackClient: slackClient, ResponseUrl: stuckServer.URL, Timeout: 50 * time.Millisecond}
+			replied := make(chan struct{})
+			go func() {
+				slashClient.PostReply("hello", "whiteboard-sydney", "")
+				close(replied)
+			}()
+			Eventually(replied).Should(BeClosed())
+		})
+	})
+
+	Describe("with an invalid signature", func() {
+		It("should reject the request", func() {
+			request, _ := http.NewRequest("POST", server.URL, bytes.NewBufferString("text=i+title"))
+			response, _ := http.DefaultClient.Do(request)
+			Expect(response.StatusCode).To(Equal(http.StatusUnauthorized))
+			Expect(restClient.PostCalledCount).To(Equal(0))
+		})
+	})
+})
@@ -0,0 +1,139 @@
+package spec
+
+import (
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+)
+
+var _ = Describe("Multiple Standups Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		restClient  *MockRestClient
+
+		registerNamedEvent, standupsEvent MessageEvent
+	)
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboard()
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+
+		registerNamedEvent = createMessageEvent("wb r singapore 12 as SG")
+		standupsEvent = createMessageEvent("wb standups")
+	})
+
+	It("should register a standup under a name", func() {
+		whiteboard.ParseMessageEvent(&registerNamedEvent)
+		Expect(slackClient.Message).To(Equal("Standup Sydney has been registered as sg! Pick it for a command like this: `wb @sg i My title`"))
+		Expect(slackClient.Status).To(Equal(THUMBS_UP))
+	})
+
+	It("should make the first named standup the default", func() {
+		newInterestingEvent := createMessageEvent("wb i something interesting")
+		whiteboard.ParseMessageEvent(&registerNamedEvent)
+		whiteboard.ParseMessageEvent(&newInterestingEvent)
+		Expect(restClient.Request.Item.StandupId).To(Equal(12))
+
+		whiteboard.ParseMessageEvent(&standupsEvent)
+		Expect(slackClient.Message).To(ContainSubstring("\n`@sg` _(default)_ *Sydney* (standup 12 on the singapore whiteboard)\n\n"))
+	})
+
+	It("should keep the default when its name is registered again", func() {
+		reregisterEvent := createMessageEvent("wb r 13 as sg")
+		newInterestingEvent := createMessageEvent("wb i something interesting")
+		whiteboard.ParseMessageEvent(&registerNamedEvent)
+		whiteboard.ParseMessageEvent(&reregisterEvent)
+		whiteboard.ParseMessageEvent(&newInterestingEvent)
+		Expect(restClient.Request.Item.StandupId).To(Equal(13))
+		Expect(restClient.Backend).To(Equal(""))
+
+		whiteboard.ParseMessageEvent(&standupsEvent)
+		Expect(slackClient.Message).To(ContainSubstring("\n`@sg` _(default)_ *Sydney* (standup 13)\n\n"))
+	})
+
+	Context("with a standup on a whiteboard that's been taken out of the config", func() {
+		BeforeEach(func() {
+			whiteboard.Store.SetStandup("whiteboard-sydney", model.Standup{Id: 12, Title: "Sydney", TimeZone: "Australia/Sydney", Backend: "unknown"})
+		})
+
+		It("should not write to another whiteboard", func() {
+			newInterestingEvent := createMessageEvent("wb i something interesting")
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			Expect(restClient.PostCalledCount).To(Equal(0))
+			Expect(slackClient.Status).To(Equal(THUMBS_DOWN + "I don't know a whiteboard called: unknown. Register the standup again with `wb r`.\n"))
+		})
+
+		It("should not read from another whiteboard", func() {
+			presentEvent := createMessageEvent("wb present")
+			whiteboard.ParseMessageEvent(&presentEvent)
+			Expect(slackClient.Message).To(Equal("I don't know a whiteboard called: unknown. Register the standup again with `wb r`."))
+			Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+		})
+	})
+
+	Context("with a default and a named standup", func() {
+		BeforeEach(func() {
+			registerStandup(whiteboard, 1)
+			whiteboard.ParseMessageEvent(&registerNamedEvent)
+		})
+
+		It("should use the default standup without a selector", func() {
+			newInterestingEvent := createMessageEvent("wb i something interesting")
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			Expect(restClient.Request.Item.StandupId).To(Equal(1))
+			Expect(restClient.Backend).To(Equal(""))
+		})
+
+		It("should use the standup picked with a selector", func() {
+			newInterestingEvent := createMessageEvent("wb @sg i! something interesting")
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			Expect(restClient.Request.Item.StandupId).To(Equal(12))
+			Expect(restClient.Request.Item.Public).To(Equal("true"))
+			Expect(restClient.Backend).To(Equal("singapore"))
+		})
+
+		It("should only pick the named standup for that command", func() {
+			namedInterestingEvent := createMessageEvent("wb @sg i something interesting")
+			newInterestingEvent := createMessageEvent("wb i something else")
+			whiteboard.ParseMessageEvent(&namedInterestingEvent)
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			Expect(restClient.Request.Item.StandupId).To(Equal(1))
+			Expect(restClient.Backend).To(Equal(""))
+		})
+
+		It("should keep updating an entry on its own standup", func() {
+			newInterestingEvent := createMessageEvent("wb @sg i something interesting")
+			setBodyEvent := createMessageEvent("wb b more info")
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			restClient.Backend = ""
+			whiteboard.ParseMessageEvent(&setBodyEvent)
+			Expect(restClient.Request.Item.StandupId).To(Equal(12))
+			Expect(restClient.Backend).To(Equal("singapore"))
+		})
+
+		It("should not guess a standup it doesn't know", func() {
+			newInterestingEvent := createMessageEvent("wb @melbourne i something interesting")
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			Expect(restClient.PostCalledCount).To(Equal(0))
+			Expect(slackClient.Message).To(Equal("There's no standup called melbourne in this channel. See the ones there are with `wb standups`"))
+			Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+		})
+
+		It("should list the standups", func() {
+			whiteboard.ParseMessageEvent(&standupsEvent)
+			Expect(slackClient.Message).To(Equal("Standups registered in this channel:\n" +
+				"\n`default` *Sydney* (standup 1)" +
+				"\n`@sg` *Sydney* (standup 12 on the singapore whiteboard)" +
+				"\n\nAdd another with `wb r <id> as <name>`, then pick it for a command like this: `wb @<name> i My title`"))
+		})
+	})
+
+	It("should ask to register before listing", func() {
+		whiteboard.ParseMessageEvent(&standupsEvent)
+		Expect(slackClient.Message).To(Equal("You haven't registered your standup yet. wb r <id> first!"))
+	})
+})
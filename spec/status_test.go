@@ -0,0 +1,142 @@
+package spec
+
+import (
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+)
+
+var _ = Describe("Status Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		store       *MockStore
+
+		statusEvent, unregisterEvent, confirmEvent MessageEvent
+	)
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboard()
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		store = whiteboard.Store.(*MockStore)
+
+		statusEvent = createMessageEvent("wb status")
+		unregisterEvent = createMessageEvent("wb unregister")
+		confirmEvent = createMessageEvent("wb unregister yes")
+	})
+
+	Describe("when standup has not been registered", func() {
+		It("should ask for standup ID", func() {
+			whiteboard.ParseMessageEvent(&statusEvent)
+			Expect(slackClient.Message).To(Equal("You haven't registered your standup yet. wb r <id> first!"))
+			whiteboard.ParseMessageEvent(&unregisterEvent)
+			Expect(slackClient.Message).To(Equal("You haven't registered your standup yet. wb r <id> first!"))
+		})
+	})
+
+	Context("with a registered standup", func() {
+		BeforeEach(func() {
+			registerStandup(whiteboard, 1)
+		})
+
+		It("should keep the standup under a namespaced key", func() {
+			Expect(store.Keys(StandupKey("whiteboard-sydney"))).To(Equal([]string{"wb:v3:channel:whiteboard-sydney:standup"}))
+		})
+
+		It("should show the standup", func() {
+			whiteboard.ParseMessageEvent(&statusEvent)
+			Expect(slackClient.Message).To(Equal("This channel is registered to standup *Sydney*\n" +
+				"\nId: 1\nTime zone: Australia/Sydney\nWhiteboard: http://localhost:3000"))
+		})
+
+		It("should remove the standup and the channel's settings", func() {
+			scheduleEvent := createMessageEvent("wb schedule weekdays 09:05")
+			newInterestingEvent := createMessageEvent("wb i something interesting")
+			whiteboard.ParseMessageEvent(&scheduleEvent)
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			Expect(store.Keys(ThreadKey("whiteboard-sydney", ""))).To(HaveLen(1))
+
+			store.SetPendingDelete("whiteboard-sydney", "dmitri", PendingDelete{ItemId: "1", Standup: model.Standup{Id: 1}})
+			whiteboard.ParseMessageEvent(&unregisterEvent)
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(slackClient.Message).To(Equal("Standup Sydney has been unregistered from this channel. Register it again with `wb r 1`"))
+			Expect(slackClient.Status).To(Equal(THUMBS_UP))
+			Expect(store.Keys(ThreadKey("whiteboard-sydney", ""))).To(BeEmpty())
+			Expect(store.Keys(EntryMessageKey("", "1"))).To(BeEmpty())
+			Expect(store.Keys(ScheduleKey("whiteboard-sydney", ""))).To(BeEmpty())
+			Expect(store.Keys(PendingDeleteKey("whiteboard-sydney", ""))).To(BeEmpty())
+			Expect(whiteboard.ScheduledChannels()).To(BeEmpty())
+
+			whiteboard.ParseMessageEvent(&statusEvent)
+			Expect(slackClient.Message).To(Equal("You haven't registered your standup yet. wb r <id> first!"))
+		})
+
+		It("should ask before removing anything", func() {
+			whiteboard.ParseMessageEvent(&unregisterEvent)
+			Expect(slackClient.Message).To(Equal("Are you sure you want to unregister standup Sydney and everything else registered in this channel? Confirm with `wb unregister yes`"))
+			Expect(store.Expiries).To(HaveKeyWithValue(PendingUnregisterKey("whiteboard-sydney", "aleung"), CONFIRMATION_EXPIRY))
+
+			whiteboard.ParseMessageEvent(&statusEvent)
+			Expect(slackClient.Message).To(HavePrefix("This channel is registered to standup *Sydney*"))
+		})
+
+		It("should only take the confirmation from whoever asked", func() {
+			otherConfirmEvent := createMessageEventWithUser("wb unregister yes", "dmitri")
+			whiteboard.ParseMessageEvent(&unregisterEvent)
+			whiteboard.ParseMessageEvent(&otherConfirmEvent)
+			Expect(slackClient.Message).To(Equal("There's nothing waiting to be unregistered. Start with `wb unregister` or `wb @name unregister` first!"))
+			Expect(slackClient.Status).To(Equal(THUMBS_DOWN))
+		})
+	})
+
+	Context("with named standups", func() {
+		BeforeEach(func() {
+			registerSydneyEvent := createMessageEvent("wb r 1 as sydney")
+			registerSingaporeEvent := createMessageEvent("wb r singapore 3 as sg")
+			whiteboard.ParseMessageEvent(&registerSydneyEvent)
+			whiteboard.ParseMessageEvent(&registerSingaporeEvent)
+		})
+
+		It("should show the picked standup", func() {
+			sgStatusEvent := createMessageEvent("wb @sg status")
+			whiteboard.ParseMessageEvent(&sgStatusEvent)
+			Expect(slackClient.Message).To(Equal("This channel is registered to standup *Sydney*\n" +
+				"\nName: @sg\nId: 3\nTime zone: Australia/Sydney\nWhiteboard: http://localhost:3000" +
+				"\n\nThere are 2 standups registered here, see them all with `wb standups`"))
+		})
+
+		It("should only remove the picked standup, and hand over the default", func() {
+			sydneyUnregisterEvent := createMessageEvent("wb @sydney unregister")
+			whiteboard.ParseMessageEvent(&sydneyUnregisterEvent)
+			Expect(slackClient.Message).To(Equal("Are you sure you want to unregister standup Sydney (@sydney)? Confirm with `wb unregister yes`"))
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(slackClient.Message).To(Equal("Standup Sydney (@sydney) has been unregistered from this channel."))
+
+			whiteboard.ParseMessageEvent(&statusEvent)
+			Expect(slackClient.Message).To(ContainSubstring("Name: @sg\nId: 3"))
+			Expect(slackClient.Message).NotTo(ContainSubstring("wb standups"))
+		})
+
+		It("should forget the picked standup's cards and waiting deletes, and keep the others", func() {
+			restClient := whiteboard.RestClient.(*MockRestClient)
+			restClient.StandupItems.Interestings = []model.Entry{{Id: "1", Title: "something interesting"}}
+			newInterestingEvent := createMessageEvent("wb @sydney i something interesting")
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			Expect(store.Keys(ThreadKey("whiteboard-sydney", ""))).To(HaveLen(1))
+			store.SetPendingDelete("whiteboard-sydney", "dmitri", PendingDelete{ItemId: "1", Standup: model.Standup{Id: 1}})
+			store.SetPendingDelete("whiteboard-sydney", "lawrence", PendingDelete{ItemId: "2", Standup: model.Standup{Id: 3, Backend: "singapore"}})
+
+			sydneyUnregisterEvent := createMessageEvent("wb @sydney unregister")
+			whiteboard.ParseMessageEvent(&sydneyUnregisterEvent)
+			whiteboard.ParseMessageEvent(&confirmEvent)
+			Expect(store.Keys(ThreadKey("whiteboard-sydney", ""))).To(BeEmpty())
+			Expect(store.Keys(EntryMessageKey("", "1"))).To(BeEmpty())
+			_, ok := store.GetPendingDelete("whiteboard-sydney", "dmitri")
+			Expect(ok).To(BeFalse())
+			_, ok = store.GetPendingDelete("whiteboard-sydney", "lawrence")
+			Expect(ok).To(BeTrue())
+		})
+	})
+})
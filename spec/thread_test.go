@@ -0,0 +1,179 @@
+package spec
+
+import (
+	"fmt"
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+	"github.com/pivotal-sydney/whiteboardbot/model"
+	"time"
+)
+
+var _ = Describe("Thread Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		restClient  *MockRestClient
+
+		newInterestingEvent MessageEvent
+		replies             int
+	)
+
+	threadReply := func(text string, user string) MessageEvent {
+		replies++
+		ev := createMessageEventWithUser(text, user)
+		ev.Timestamp = fmt.Sprintf("1420156800.%06d", 300 + replies)
+		ev.ThreadTimestamp = MOCK_TIMESTAMP
+		return ev
+	}
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+
+		restClient.StandupItems = model.StandupItems{}
+		restClient.StandupItems.Interestings = []model.Entry{model.Entry{Id: "1", Title: "something interesting", Author: "Andrew Leung", Date: "2015-01-02"}}
+
+		newInterestingEvent = createMessageEvent("wb i something interesting")
+		whiteboard.ParseMessageEvent(&newInterestingEvent)
+	})
+
+	It("should set the body from a body reply", func() {
+		replyEvent := threadReply("body Pizza at 6pm", "UUserId2")
+		whiteboard.ParseMessageEvent(&replyEvent)
+		Expect(restClient.PostCalledCount).To(Equal(2))
+		Expect(restClient.Request.Method).To(Equal("patch"))
+		Expect(restClient.Request.Id).To(Equal("1"))
+		Expect(restClient.Request.Item.Description).To(Equal("Pizza at 6pm"))
+		Expect(slackClient.UpdateEntryCalledCount).To(Equal(1))
+	})
+
+	It("should run detail commands in the reply", func() {
+		dateEvent := threadReply("date 2015-12-01", "UUserId2")
+		whiteboard.ParseMessageEvent(&dateEvent)
+		Expect(restClient.Request.Item.Date).To(Equal("2015-12-01"))
+
+		publicEvent := threadReply("wb pu", "UUserId2")
+		whiteboard.ParseMessageEvent(&publicEvent)
+		Expect(restClient.Request.Item.Public).To(Equal("true"))
+		Expect(restClient.Request.Item.Description).To(Equal(""))
+	})
+
+	It("should keep the replier's own current entry", func() {
+		draft := model.Interesting{Entry: &model.Entry{Id: "2", Title: "my own draft", Author: "User Two", Date: "2015-01-02", ItemKind: "Interesting", StandupId: 1}}
+		whiteboard.Store.SetEntry("user-name-two", draft)
+		replyEvent := threadReply("title something else", "UUserId2")
+		whiteboard.ParseMessageEvent(&replyEvent)
+		Expect(restClient.Request.Id).To(Equal("1"))
+
+		current, ok := whiteboard.Store.GetEntry("user-name-two")
+		Expect(ok).To(BeTrue())
+		Expect(current.GetEntry().Id).To(Equal("2"))
+		Expect(current.GetEntry().Title).To(Equal("my own draft"))
+
+		setBodyEvent := createMessageEventWithUser("wb b more info", "UUserId2")
+		whiteboard.ParseMessageEvent(&setBodyEvent)
+		Expect(restClient.Request.Id).To(Equal("2"))
+		Expect(restClient.Request.Item.Title).To(Equal("my own draft"))
+	})
+
+	It("should leave the entry alone when people chat in the thread", func() {
+		replyEvent := threadReply("Pizza at 6pm", "UUserId2")
+		whiteboard.ParseMessageEvent(&replyEvent)
+		Expect(restClient.PostCalledCount).To(Equal(1))
+		Expect(slackClient.UpdateEntryCalledCount).To(Equal(0))
+	})
+
+	It("should keep the author's current entry up to date when they reply", func() {
+		replyEvent := threadReply("wb b Pizza at 6pm", "aleung")
+		setTitleEvent := createMessageEvent("wb t something else")
+		whiteboard.ParseMessageEvent(&replyEvent)
+		whiteboard.ParseMessageEvent(&setTitleEvent)
+		Expect(restClient.Request.Item.Title).To(Equal("something else"))
+		Expect(restClient.Request.Item.Description).To(Equal("Pizza at 6pm"))
+	})
+
+	It("should keep both changes when a reply and the author update the entry at once", func() {
+		restClient.UpdateItems = true
+		restClient.PostDelay = 50 * time.Millisecond
+		dispatcher := NewDispatcher(whiteboard.ParseMessageEvent)
+		dispatcher.Key = whiteboard.DispatchKey
+		setTitleEvent := createMessageEvent("wb t something else")
+		replyEvent := threadReply("body Pizza at 6pm", "UUserId2")
+		dispatcher.Dispatch(&setTitleEvent)
+		dispatcher.Dispatch(&replyEvent)
+		Eventually(restClient.PostCount).Should(Equal(3))
+		Expect(restClient.Request.Item.Title).To(Equal("something else"))
+		Expect(restClient.Request.Item.Description).To(Equal("Pizza at 6pm"))
+
+		publicEvent := createMessageEvent("wb public")
+		whiteboard.ParseMessageEvent(&publicEvent)
+		Expect(restClient.Request.Item.Title).To(Equal("something else"))
+		Expect(restClient.Request.Item.Description).To(Equal("Pizza at 6pm"))
+	})
+
+	It("should only read whole command names as commands without wb", func() {
+		replyEvent := threadReply("b sure to bring snacks", "UUserId2")
+		whiteboard.ParseMessageEvent(&replyEvent)
+		Expect(restClient.PostCalledCount).To(Equal(1))
+	})
+
+	It("should queue updates from the thread by entry rather than by who sent them", func() {
+		updateEvent := threadReply("title something else", "UUserId2")
+		otherUpdateEvent := threadReply("wb t something else", "aleung")
+		chatEvent := threadReply("Pizza at 6pm", "UUserId2")
+		commandEvent := threadReply("wb h help me", "UUserId2")
+		Expect(whiteboard.DispatchKey(&updateEvent)).To(Equal("item::1"))
+		Expect(whiteboard.DispatchKey(&otherUpdateEvent)).To(Equal("item::1"))
+		Expect(whiteboard.DispatchKey(&chatEvent)).To(Equal("UUserId2"))
+		Expect(whiteboard.DispatchKey(&commandEvent)).To(Equal("UUserId2"))
+	})
+
+	It("should leave other commands to run as usual", func() {
+		newHelpEvent := threadReply("wb h help me", "UUserId2")
+		whiteboard.ParseMessageEvent(&newHelpEvent)
+		Expect(restClient.Request.Method).To(Equal(""))
+		Expect(restClient.Request.Item.Kind).To(Equal("Help"))
+	})
+
+	It("should only follow the thread for as long as the entry is kept", func() {
+		store := whiteboard.Store.(*MockStore)
+		Expect(store.Expiries).To(HaveKeyWithValue(ThreadKey("whiteboard-sydney", MOCK_TIMESTAMP), ENTRY_EXPIRY))
+		Expect(store.Expiries).To(HaveKeyWithValue(EntryMessageKey("", "1"), ENTRY_EXPIRY))
+	})
+
+	It("should update the entry on the whiteboard of its card when another has the same item id", func() {
+		registerNamedEvent := createMessageEvent("wb r singapore 12 as sg")
+		namedInterestingEvent := createMessageEvent("wb @sg i something interesting")
+		whiteboard.ParseMessageEvent(&registerNamedEvent)
+		whiteboard.ParseMessageEvent(&namedInterestingEvent)
+		threadEntry, _ := whiteboard.Store.Get(ThreadKey("whiteboard-sydney", MOCK_TIMESTAMP))
+		Expect(threadEntry).To(Equal("singapore:1"))
+
+		replyEvent := threadReply("body Pizza at 6pm", "UUserId2")
+		whiteboard.ParseMessageEvent(&replyEvent)
+		Expect(restClient.Request.Id).To(Equal("1"))
+		Expect(restClient.Request.Item.Description).To(Equal("Pizza at 6pm"))
+		Expect(restClient.Backend).To(Equal("singapore"))
+	})
+
+	It("should ignore replies in threads it didn't start", func() {
+		replyEvent := threadReply("body Pizza at 6pm", "UUserId2")
+		replyEvent.ThreadTimestamp = "1420156800.000900"
+		whiteboard.ParseMessageEvent(&replyEvent)
+		Expect(restClient.PostCalledCount).To(Equal(1))
+	})
+
+	It("should stop following the thread once the entry is deleted", func() {
+		deleteEvent := createMessageEvent("wb delete")
+		confirmEvent := createMessageEvent("wb delete yes")
+		whiteboard.ParseMessageEvent(&deleteEvent)
+		whiteboard.ParseMessageEvent(&confirmEvent)
+
+		replyEvent := threadReply("body Pizza at 6pm", "UUserId2")
+		whiteboard.ParseMessageEvent(&replyEvent)
+		Expect(restClient.PostCalledCount).To(Equal(1))
+	})
+})
@@ -12,18 +12,18 @@ var _ = Describe("Upload Integration", func() {
 		whiteboard WhiteboardApp
 		slackClient *MockSlackClient
 		uploadEvent MessageEvent
-		file *File
+		file File
 	)
 
 	BeforeEach(func() {
 		whiteboard = createWhiteboardAndRegisterStandup(1)
 		slackClient = whiteboard.SlackClient.(*MockSlackClient)
 
-		file = &File{}
+		file = File{}
 		file.Permalink = "http://upload/link"
 		file.InitialComment = Comment{Comment: "Body of the event"}
 		file.Title = "wb i My Title"
-		uploadEvent = MessageEvent{Msg: Msg{Upload: true, File: file, Channel: "whiteboard-sydney"}}
+		uploadEvent = MessageEvent{Msg: Msg{Upload: true, Files: []File{file}, Channel: "whiteboard-sydney"}}
 	})
 
 	Describe("when uploading an image", func() {
@@ -37,7 +37,7 @@ var _ = Describe("Upload Integration", func() {
 
 		Context("with invalid keyword", func() {
 			BeforeEach(func() {
-				file.Title = "wb nonKeyword"
+				uploadEvent.Files[0].Title = "wb nonKeyword"
 			})
 
 			It("should handle default response", func() {
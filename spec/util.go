@@ -1,8 +1,10 @@
 package spec
 
 import (
+	"bytes"
 	. "github.com/nlopes/slack"
 	"github.com/pivotal-sydney/whiteboardbot/app"
+	"net/http"
 	"strconv"
 )
 
@@ -33,3 +35,12 @@ func registerStandup(whiteboard app.WhiteboardApp, standupId int) {
 	registrationEvent := createMessageEvent("wb r " + strconv.Itoa(standupId))
 	whiteboard.ParseMessageEvent(&registrationEvent)
 }
+
+func postSignedForm(serverUrl string, body string) (*http.Response, error) {
+	timestamp := strconv.FormatInt(MockClock{}.Now().Unix(), 10)
+	request, _ := http.NewRequest("POST", serverUrl, bytes.NewBufferString(body))
+	request.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	request.Header.Set("X-Slack-Request-Timestamp", timestamp)
+	request.Header.Set("X-Slack-Signature", app.SignSlackRequest("secret", timestamp, []byte(body)))
+	return http.DefaultClient.Do(request)
+}
@@ -0,0 +1,76 @@
+package spec
+
+import (
+	. "github.com/nlopes/slack"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	. "github.com/pivotal-sydney/whiteboardbot/app"
+)
+
+var _ = Describe("Visibility Integration", func() {
+	var (
+		whiteboard  WhiteboardApp
+		slackClient *MockSlackClient
+		restClient  *MockRestClient
+
+		newInterestingEvent, newPublicInterestingEvent, publicEvent, privateEvent MessageEvent
+	)
+
+	BeforeEach(func() {
+		whiteboard = createWhiteboardAndRegisterStandup(1)
+		slackClient = whiteboard.SlackClient.(*MockSlackClient)
+		restClient = whiteboard.RestClient.(*MockRestClient)
+
+		newInterestingEvent = createMessageEvent("wb i something interesting")
+		newPublicInterestingEvent = createMessageEvent("wb i! something interesting")
+		publicEvent = createMessageEvent("wb public")
+		privateEvent = createMessageEvent("wb private")
+	})
+
+	Describe("creating an entry", func() {
+		It("should be private by default", func() {
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			Expect(restClient.Request.Item.Public).To(Equal("false"))
+			Expect(slackClient.Entry.String()).NotTo(ContainSubstring("_Public_"))
+		})
+
+		It("should be public when the command ends with !", func() {
+			whiteboard.ParseMessageEvent(&newPublicInterestingEvent)
+			Expect(slackClient.Entry.Title).To(Equal("something interesting"))
+			Expect(restClient.Request.Item.Public).To(Equal("true"))
+			Expect(slackClient.Entry.String()).To(HaveSuffix("\n_Public_"))
+		})
+
+		It("should only make the new entry public", func() {
+			whiteboard.ParseMessageEvent(&newPublicInterestingEvent)
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+			Expect(restClient.Request.Item.Public).To(Equal("false"))
+		})
+	})
+
+	Describe("with an entry started", func() {
+		BeforeEach(func() {
+			whiteboard.ParseMessageEvent(&newInterestingEvent)
+		})
+
+		It("should make the entry public", func() {
+			whiteboard.ParseMessageEvent(&publicEvent)
+			Expect(restClient.Request.Method).To(Equal("patch"))
+			Expect(restClient.Request.Item.Public).To(Equal("true"))
+			Expect(slackClient.Status).To(Equal(THUMBS_UP + "INTERESTING\n"))
+		})
+
+		It("should make the entry private again", func() {
+			whiteboard.ParseMessageEvent(&publicEvent)
+			whiteboard.ParseMessageEvent(&privateEvent)
+			Expect(restClient.Request.Item.Public).To(Equal("false"))
+		})
+	})
+
+	Describe("with no entry started", func() {
+		It("should give a hint on how to start entry", func() {
+			whiteboard.ParseMessageEvent(&publicEvent)
+			Expect(slackClient.Message).To(Equal("Hey, you forgot to start new entry. Start with one of `wb [face interesting help event] [title]` first!"))
+		})
+	})
+})